@@ -1,11 +1,18 @@
 package main
 
 import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
 	"fmt"
+	"github.com/hallidave/mibtool/export"
 	"github.com/hallidave/mibtool/smi"
 	"os"
 	"os/user"
 	"path/filepath"
+	"strings"
+	"text/tabwriter"
 )
 
 func userMibDir() string {
@@ -20,6 +27,18 @@ func userMibDir() string {
 	return defaultDir
 }
 
+func usage() {
+	fmt.Printf("Usage: %v dump [module]\n", os.Args[0])
+	fmt.Printf("       %v search [options] query\n", os.Args[0])
+	fmt.Printf("       %v grep [options] words\n", os.Args[0])
+	fmt.Printf("       %v annotate [options] [walkfile]\n", os.Args[0])
+	fmt.Printf("       %v table [options] [walkfile]\n", os.Args[0])
+	fmt.Printf("       %v export [options] [module ...]\n", os.Args[0])
+	fmt.Printf("       %v yang [options] module ...\n", os.Args[0])
+	fmt.Printf("       %v gogen [options] module ...\n", os.Args[0])
+	os.Exit(1)
+}
+
 func dumpModule(mib *smi.MIB, modName string) {
 	mib.VisitSymbols(func(sym *smi.Symbol, oid smi.OID) {
 		if sym.Module.Name == modName {
@@ -28,16 +47,437 @@ func dumpModule(mib *smi.MIB, modName string) {
 	})
 }
 
-func main() {
-	if len(os.Args) != 3 || os.Args[1] != "dump" {
-		fmt.Printf("Usage: %v dump [module]\n", os.Args[0])
+func dump(args []string) {
+	if len(args) != 1 {
+		usage()
+	}
+	mib := smi.NewMIB(userMibDir())
+	err := mib.LoadModules(args[0])
+	if err != nil {
+		fmt.Println(err)
+	}
+	dumpModule(mib, args[0])
+}
+
+// listFlag is a flag that may be given several times or as a comma separated list.
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	*l = append(*l, strings.Split(value, ",")...)
+	return nil
+}
+
+var nodeTypes = map[string]smi.NodeType{
+	"module":       smi.NodeModuleID,
+	"oid":          smi.NodeObjectID,
+	"object":       smi.NodeObjectType,
+	"notification": smi.NodeNotification,
+}
+
+func search(args []string) {
+	flags := flag.NewFlagSet("search", flag.ExitOnError)
+	var modules, types, status, access listFlag
+	flags.Var(&modules, "module", "only search the `modules`")
+	flags.Var(&types, "type", "only find symbols of the `types` module, oid, object or notification")
+	flags.Var(&status, "status", "only find symbols with the `status` values")
+	flags.Var(&access, "access", "only find objects with the `access` values")
+	regexp := flags.Bool("regexp", false, "the query is a regular expression")
+	fuzzy := flags.Int("fuzzy", 2, "maximum edit `distance` for fuzzy matches, 0 for none")
+	limit := flags.Int("limit", 50, "maximum `number` of results, 0 for no limit")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	opts := smi.SearchOptions{
+		Modules:     modules,
+		Status:      status,
+		Access:      access,
+		Regexp:      *regexp,
+		MaxDistance: *fuzzy,
+		Limit:       *limit,
+	}
+	for _, name := range types {
+		t, ok := nodeTypes[name]
+		if !ok {
+			fmt.Printf("unknown type: %s\n", name)
+			os.Exit(1)
+		}
+		opts.Types = append(opts.Types, t)
+	}
+
+	mib := smi.NewMIB(userMibDir())
+	err := mib.LoadModules()
+	if err != nil {
+		fmt.Println(err)
+	}
+	results, err := mib.Search(flags.Arg(0), opts)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	for _, result := range results {
+		fmt.Printf("%-40s %-30s %s\n", result.Symbol, result.OID, result.Match)
+	}
+}
+
+func grep(args []string) {
+	flags := flag.NewFlagSet("grep", flag.ExitOnError)
+	limit := flags.Int("limit", 50, "maximum `number` of results, 0 for no limit")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	mib := smi.NewMIB(userMibDir())
+	err := mib.LoadModules()
+	if err != nil {
+		fmt.Println(err)
+	}
+	for _, match := range mib.SearchText(strings.Join(flags.Args(), " "), *limit) {
+		fmt.Printf("%-40s %-11s %s\n", match.Name(), match.Field, match.Snippet)
+	}
+}
+
+// readWalk loads all modules and parses the walk file named by the
+// arguments, or the standard input if there is none.
+func readWalk(flags *flag.FlagSet) (*smi.MIB, []smi.WalkVarbind) {
+	if flags.NArg() > 1 {
+		flags.Usage()
 		os.Exit(1)
 	}
+	in := os.Stdin
+	if flags.NArg() == 1 {
+		f, err := os.Open(flags.Arg(0))
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		in = f
+	}
 
 	mib := smi.NewMIB(userMibDir())
-	err := mib.LoadModules(os.Args[2])
+	err := mib.LoadModules()
+	if err != nil {
+		fmt.Println(err)
+	}
+	walk, err := mib.ParseWalk(in)
 	if err != nil {
 		fmt.Println(err)
+		os.Exit(1)
+	}
+	return mib, walk
+}
+
+func annotate(args []string) {
+	flags := flag.NewFlagSet("annotate", flag.ExitOnError)
+	brackets := flags.Bool("brackets", false, "show index values in brackets")
+	flags.Parse(args)
+	mib, walk := readWalk(flags)
+
+	varbinds := make([]smi.Varbind, len(walk))
+	for i, vb := range walk {
+		varbinds[i] = vb.Varbind
+	}
+	opts := smi.FormatOptions{Index: smi.IndexValues}
+	if *brackets {
+		opts.Index = smi.IndexBrackets
+	}
+	for i, r := range mib.RenderVarbinds(varbinds) {
+		r.Name = mib.FormatOID(r.OID, opts)
+		if walk[i].Type.IsException() {
+			fmt.Printf("%s = %s\n", r.Name, walk[i].Type)
+			continue
+		}
+		fmt.Println(r)
+	}
+}
+
+// jsonTable is the JSON output of the table command.
+type jsonTable struct {
+	Table   string    `json:"table"`
+	Index   []string  `json:"index"`
+	Columns []string  `json:"columns"`
+	Rows    []jsonRow `json:"rows"`
+}
+
+type jsonRow struct {
+	Index  []string          `json:"index"`
+	Values map[string]string `json:"values"`
+}
+
+func table(args []string) {
+	flags := flag.NewFlagSet("table", flag.ExitOnError)
+	format := flags.String("format", "text", "output `format` text, csv or json")
+	name := flags.String("table", "", "only show the table or row with this `name`, or the table it augments")
+	flags.Parse(args)
+	mib, walk := readWalk(flags)
+
+	var varbinds []smi.Varbind
+	for _, vb := range walk {
+		if !vb.Type.IsException() {
+			varbinds = append(varbinds, vb.Varbind)
+		}
+	}
+	tables := mib.Tables(varbinds)
+	if *name != "" {
+		oid, err := mib.OID(*name)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		sym, _ := mib.Symbol(oid)
+		rows := augmentedRows(mib, sym)
+		var selected []*smi.Table
+		for _, t := range tables {
+			if t.Symbol == sym || rows[t.Row] {
+				selected = append(selected, t)
+			}
+		}
+		if len(selected) == 0 {
+			fmt.Printf("no rows of %s found\n", *name)
+			os.Exit(1)
+		}
+		tables = selected
+	}
+
+	switch *format {
+	case "text":
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for i, t := range tables {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintln(w, t.Symbol)
+			for _, record := range tableRecords(t) {
+				fmt.Fprintln(w, strings.Join(record, "\t"))
+			}
+		}
+		w.Flush()
+	case "csv":
+		if len(tables) > 1 {
+			fmt.Println("several tables found, select one with -table")
+			os.Exit(1)
+		}
+		w := csv.NewWriter(os.Stdout)
+		for _, t := range tables {
+			w.WriteAll(tableRecords(t))
+		}
+	case "json":
+		out := make([]jsonTable, len(tables))
+		for i, t := range tables {
+			out[i] = jsonTable{Table: t.Symbol.String(), Index: symbolNames(t.Index), Columns: symbolNames(t.Columns)}
+			for _, row := range t.Rows {
+				out[i].Rows = append(out[i].Rows, jsonRow{Index: row.Key, Values: row.Rendered})
+			}
+		}
+		b, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Println(string(b))
+	default:
+		fmt.Printf("unknown format: %s\n", *format)
+		os.Exit(1)
+	}
+}
+
+// augmentedRows returns the row of the table or row sym and the rows it
+// AUGMENTS, directly or indirectly. The columns of augmenting rows are merged
+// into the table of the row they augment.
+func augmentedRows(mib *smi.MIB, sym *smi.Symbol) map[*smi.Symbol]bool {
+	rows := make(map[*smi.Symbol]bool)
+	if sym != nil && sym.IsTable() {
+		for _, child := range sym.ChildByID {
+			if child.IsRow() {
+				sym = child
+			}
+		}
+	}
+	for sym != nil && sym.IsRow() && !rows[sym] {
+		rows[sym] = true
+		if sym.Node.Augments == "" {
+			break
+		}
+		sym = mib.LookupSymbol(sym.Module, sym.Node.Augments)
+	}
+	return rows
+}
+
+// tableRecords returns a header with the names of the index objects and
+// columns of the table followed by a record for each row.
+func tableRecords(t *smi.Table) [][]string {
+	header := append(symbolNames(t.Index), symbolNames(t.Columns)...)
+	if len(t.Index) == 0 {
+		header = append([]string{"index"}, header...)
+	}
+	records := [][]string{header}
+	for _, row := range t.Rows {
+		record := append([]string{}, row.Key...)
+		for len(record) < len(header)-len(t.Columns) {
+			record = append(record, "")
+		}
+		for _, column := range t.Columns {
+			record = append(record, row.Rendered[column.Name])
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func symbolNames(syms []*smi.Symbol) []string {
+	names := make([]string, len(syms))
+	for i, sym := range syms {
+		names[i] = sym.Name
+	}
+	return names
+}
+
+func exportModules(args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	format := flags.String("format", "json", "output `format` json, tree, identifiers, types, metrics or xml")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %v export [options] [module ...]\n", os.Args[0])
+		fmt.Fprintln(flags.Output(), "The tree, identifiers, types, metrics and xml formats are modelled on")
+		fmt.Fprintln(flags.Output(), "smidump but not verified against it. Conformance definitions, such as")
+		fmt.Fprintln(flags.Output(), "OBJECT-GROUP and MODULE-COMPLIANCE, are not exported in any format.")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	mib := smi.NewMIB(userMibDir())
+	err := mib.LoadModules(flags.Args()...)
+	if err != nil {
+		fmt.Println(err)
+	}
+	switch *format {
+	case "json":
+		err = export.WriteJSON(os.Stdout, mib, flags.Args()...)
+	case "tree":
+		err = export.WriteTree(os.Stdout, mib, flags.Args()...)
+	case "identifiers":
+		err = export.WriteIdentifiers(os.Stdout, mib, flags.Args()...)
+	case "types":
+		err = export.WriteTypes(os.Stdout, mib, flags.Args()...)
+	case "metrics":
+		err = export.WriteMetrics(os.Stdout, mib, flags.Args()...)
+	case "xml":
+		err = export.WriteXML(os.Stdout, mib, flags.Args()...)
+	default:
+		err = fmt.Errorf("unknown format: %s", *format)
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func yang(args []string) {
+	flags := flag.NewFlagSet("yang", flag.ExitOnError)
+	dir := flags.String("dir", "", "write each module to `directory`/MODULE.yang")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	mib := smi.NewMIB(userMibDir())
+	err := mib.LoadModules(flags.Args()...)
+	if err != nil {
+		fmt.Println(err)
+	}
+	for _, modName := range flags.Args() {
+		if *dir == "" {
+			err = export.WriteYANG(os.Stdout, mib, modName)
+		} else {
+			err = writeYANGFile(filepath.Join(*dir, modName+".yang"), mib, modName)
+		}
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+}
+
+func writeYANGFile(path string, mib *smi.MIB, modName string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := export.WriteYANG(f, mib, modName); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func gogen(args []string) {
+	flags := flag.NewFlagSet("gogen", flag.ExitOnError)
+	pkgName := flags.String("package", "", "`name` of the generated package (default the lower-case first module name)")
+	output := flags.String("o", "", "write the source to `file` instead of standard output")
+	mibDir := flags.String("mibs", userMibDir(), "`directory` of the MIB modules")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(1)
+	}
+	if *pkgName == "" {
+		*pkgName = strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(flags.Arg(0)))
+	}
+
+	// Errors go to the standard error, as the source may be written to the
+	// standard output, as under go:generate.
+	mib := smi.NewMIB(*mibDir)
+	err := mib.LoadModules(flags.Args()...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	var buf bytes.Buffer
+	err = export.WriteGo(&buf, mib, *pkgName, flags.Args()...)
+	if err == nil {
+		if *output == "" {
+			_, err = os.Stdout.Write(buf.Bytes())
+		} else {
+			err = os.WriteFile(*output, buf.Bytes(), 0644)
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	switch os.Args[1] {
+	case "dump":
+		dump(os.Args[2:])
+	case "search":
+		search(os.Args[2:])
+	case "grep":
+		grep(os.Args[2:])
+	case "annotate":
+		annotate(os.Args[2:])
+	case "table":
+		table(os.Args[2:])
+	case "export":
+		exportModules(os.Args[2:])
+	case "yang":
+		yang(os.Args[2:])
+	case "gogen":
+		gogen(os.Args[2:])
+	default:
+		usage()
 	}
-	dumpModule(mib, os.Args[2])
 }
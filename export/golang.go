@@ -0,0 +1,456 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hallidave/mibtool/smi"
+)
+
+// WriteGo writes the source of a Go package named pkgName for the modules of
+// the MIB named by modNames, or for all of the loaded modules. The package
+// declares an OID string type with a constant for each symbol, named after
+// the symbol with an OID suffix, as in IfDescrOID. Each enumerated INTEGER
+// syntax of a scalar, column or INDEX object, including INDEX objects of
+// other modules, becomes a type with a constant for each
+// value and a String method, named after the textual convention that
+// defines the values or else after the object. Each table row becomes a
+// struct with a field for each INDEX object and column, and a DecodeIndex
+// method that sets the INDEX fields from the index of an instance OID.
+// The generated package only imports the standard library. WriteGo returns
+// an error if two definitions map to the same Go name.
+func WriteGo(w io.Writer, mib *smi.MIB, pkgName string, modNames ...string) error {
+	mods, err := loadedModules(mib, modNames)
+	if err != nil {
+		return err
+	}
+	g := &goWriter{mib: mib, names: make(map[string]string), enums: make(map[string]string)}
+	g.declare("OID", "the OID type")
+	var body bytes.Buffer
+	for _, mod := range mods {
+		g.constants(&body, mod)
+	}
+	for _, mod := range mods {
+		for _, sym := range moduleSymbols(mod) {
+			if sym.IsScalar() || sym.IsColumn() {
+				g.enum(&body, sym)
+			}
+		}
+	}
+	// The INDEX objects of the rows may be defined by other modules
+	for _, mod := range mods {
+		for _, sym := range moduleSymbols(mod) {
+			if !sym.IsRow() {
+				continue
+			}
+			index, _, _ := g.index(sym)
+			for _, obj := range index {
+				g.enum(&body, obj)
+			}
+		}
+	}
+	for _, mod := range mods {
+		for _, sym := range moduleSymbols(mod) {
+			if sym.IsRow() {
+				g.row(&body, sym)
+			}
+		}
+	}
+	if g.err != nil {
+		return g.err
+	}
+
+	var src bytes.Buffer
+	names := make([]string, len(mods))
+	for i, mod := range mods {
+		names[i] = mod.Name
+	}
+	fmt.Fprintf(&src, "// Code generated by mib gogen from %s; DO NOT EDIT.\n\n", strings.Join(names, ", "))
+	fmt.Fprintf(&src, "package %s\n\n", pkgName)
+	var imports []string
+	if len(g.enums) > 0 || g.rows {
+		imports = append(imports, "fmt")
+	}
+	if g.ip {
+		imports = append(imports, "net")
+	}
+	if g.rows {
+		imports = append(imports, "strconv", "strings")
+	}
+	if len(imports) > 0 {
+		fmt.Fprintf(&src, "import (\n")
+		for _, imp := range imports {
+			fmt.Fprintf(&src, "\t%q\n", imp)
+		}
+		fmt.Fprintf(&src, ")\n\n")
+	}
+	fmt.Fprintf(&src, "// An OID is an object identifier in dotted form.\ntype OID string\n\n")
+	src.Write(body.Bytes())
+	if g.rows {
+		src.WriteString(goIndexReader)
+	}
+	out, err := format.Source(src.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// goName returns the exported Go name for an SMI descriptor, dropping the
+// hyphens and capitalizing the letters that follow them, as in Mib2 for
+// mib-2.
+func goName(name string) string {
+	parts := strings.Split(name, "-")
+	for i, part := range parts {
+		if part != "" {
+			parts[i] = strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// goWriter generates the declarations of a Go package. The first error is
+// kept in err.
+type goWriter struct {
+	mib   *smi.MIB
+	names map[string]string
+	enums map[string]string
+	rows  bool
+	ip    bool
+	err   error
+}
+
+// declare records that the Go name is used by the definition what, setting
+// err if the name is already used.
+func (g *goWriter) declare(name, what string) {
+	if prev, ok := g.names[name]; ok && g.err == nil {
+		g.err = fmt.Errorf("Go name %s of %s is already used by %s", name, what, prev)
+	}
+	g.names[name] = what
+}
+
+// constants writes the OID constants of a module.
+func (g *goWriter) constants(w *bytes.Buffer, mod *smi.Module) {
+	syms := moduleSymbols(mod)
+	if len(syms) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "// OIDs of the symbols defined by %s.\nconst (\n", mod.Name)
+	for _, sym := range syms {
+		name := goName(sym.Name) + "OID"
+		g.declare(name, sym.String())
+		fmt.Fprintf(w, "\t%s OID = %q\n", name, symbolOID(sym).String())
+	}
+	fmt.Fprintf(w, ")\n\n")
+}
+
+// A goSyntax is the syntax of an object resolved through the types it is
+// derived from. Base is the SMI base type, and Enums, Sizes and Hint are
+// the first named numbers, SIZE constraint and display hint found from the
+// object to its base type. EnumType is the type that defines the Enums,
+// which is nil if the object defines them, and EnumModule is its module.
+type goSyntax struct {
+	Base       string
+	Enums      []smi.NamedNumber
+	EnumType   *smi.Type
+	EnumModule *smi.Module
+	Sizes      []smi.Range
+	Hint       string
+}
+
+// resolve resolves the syntax of an object defined by module mod.
+func (g *goWriter) resolve(mod *smi.Module, syntax smi.Syntax) goSyntax {
+	s := goSyntax{Enums: syntax.Enums, Sizes: syntax.Sizes}
+	for depth := 0; depth < smi.MaxTypeDepth && mod != nil; depth++ {
+		if _, ok := libsmiBaseTypes[syntax.Type]; ok {
+			s.Base = syntax.Type
+			break
+		}
+		var t *smi.Type
+		mod, t = g.mib.FindType(mod, syntax.Type)
+		if t == nil {
+			break
+		}
+		syntax = t.Syntax
+		if len(s.Enums) == 0 && len(syntax.Enums) > 0 {
+			s.Enums = syntax.Enums
+			s.EnumType = t
+			s.EnumModule = mod
+		}
+		if len(s.Sizes) == 0 {
+			s.Sizes = syntax.Sizes
+		}
+		if s.Hint == "" {
+			s.Hint = t.DisplayHint
+		}
+	}
+	return s
+}
+
+// isEnum returns true if the syntax is an enumerated INTEGER.
+func (s goSyntax) isEnum() bool {
+	return len(s.Enums) > 0 && (s.Base == "INTEGER" || s.Base == "Integer32")
+}
+
+// enumName returns the name of the Go type of an enumerated syntax of sym.
+func (s goSyntax) enumName(sym *smi.Symbol) string {
+	if s.EnumType != nil {
+		return goName(s.EnumType.Name)
+	}
+	return goName(sym.Name)
+}
+
+// goType returns the Go type of the values of sym.
+func (g *goWriter) goType(sym *smi.Symbol, s goSyntax) string {
+	if s.isEnum() {
+		return s.enumName(sym)
+	}
+	switch s.Base {
+	case "INTEGER", "Integer32":
+		return "int32"
+	case "Unsigned32", "Counter32", "Gauge32", "TimeTicks":
+		return "uint32"
+	case "Counter64":
+		return "uint64"
+	case "OCTET STRING":
+		if isTextHint(s.Hint) {
+			return "string"
+		}
+	case "IpAddress":
+		g.ip = true
+		return "net.IP"
+	case "OBJECT IDENTIFIER":
+		return "OID"
+	}
+	return "[]byte"
+}
+
+// enum writes the type of an enumerated syntax of sym, unless it is not
+// enumerated or the type of the same definition has already been written.
+func (g *goWriter) enum(w *bytes.Buffer, sym *smi.Symbol) {
+	if sym.Node == nil || sym.Node.Syntax == nil {
+		return
+	}
+	s := g.resolve(sym.Module, *sym.Node.Syntax)
+	if !s.isEnum() {
+		return
+	}
+	name := s.enumName(sym)
+	what := sym.String()
+	if s.EnumType != nil {
+		what = s.EnumModule.Name + "::" + s.EnumType.Name
+	}
+	if g.enums[name] == what {
+		return
+	}
+	g.enums[name] = what
+	g.declare(name, what)
+	fmt.Fprintf(w, "// %s holds the values of %s.\ntype %s int\n\n", name, what, name)
+	fmt.Fprintf(w, "// Values of %s.\nconst (\n", name)
+	for _, enum := range s.Enums {
+		g.declare(name+goName(enum.Name), what+" value "+enum.Name)
+		fmt.Fprintf(w, "\t%s%s %s = %d\n", name, goName(enum.Name), name, enum.Value)
+	}
+	fmt.Fprintf(w, ")\n\n")
+	fmt.Fprintf(w, "func (v %s) String() string {\n\tswitch v {\n", name)
+	for _, enum := range s.Enums {
+		fmt.Fprintf(w, "\tcase %s%s:\n\t\treturn %q\n", name, goName(enum.Name), enum.Name)
+	}
+	fmt.Fprintf(w, "\t}\n\treturn fmt.Sprintf(\"%s(%%d)\", int(v))\n}\n\n", name)
+}
+
+// index returns the INDEX objects of a table row, or of the row it augments,
+// and whether each is IMPLIED. It sets err and returns false if an object
+// is not found.
+func (g *goWriter) index(row *smi.Symbol) ([]*smi.Symbol, []bool, bool) {
+	var index []*smi.Symbol
+	var implied []bool
+	for _, indexName := range indexNames(g.mib, row) {
+		obj := g.mib.LookupSymbol(row.Module, strings.TrimPrefix(indexName, "*"))
+		if obj == nil || obj.Node == nil || obj.Node.Syntax == nil {
+			if g.err == nil {
+				g.err = fmt.Errorf("INDEX object %s of %s not found", indexName, row)
+			}
+			return nil, nil, false
+		}
+		index = append(index, obj)
+		implied = append(implied, strings.HasPrefix(indexName, "*"))
+	}
+	return index, implied, true
+}
+
+// row writes the struct of a table row and its DecodeIndex method. INDEX
+// objects that are not columns of the row come first in the struct.
+func (g *goWriter) row(w *bytes.Buffer, row *smi.Symbol) {
+	g.rows = true
+	name := goName(row.Name)
+	g.declare(name, row.String())
+
+	index, implied, ok := g.index(row)
+	if !ok {
+		return
+	}
+	// An INDEX object may occur more than once, as in RMON2-MIB, so the
+	// fields of repeated objects are numbered by their position.
+	type rowField struct {
+		name string
+		obj  *smi.Symbol
+	}
+	var fields []rowField
+	indexFields := make([]string, len(index))
+	for i, obj := range index {
+		indexFields[i] = goName(obj.Name)
+		if slices.Index(index, obj) < i {
+			indexFields[i] += strconv.Itoa(i + 1)
+		} else if obj.Parent == row {
+			continue
+		}
+		fields = append(fields, rowField{indexFields[i], obj})
+	}
+	for _, column := range row.Children() {
+		if column.IsColumn() && column.Node.Syntax != nil {
+			fields = append(fields, rowField{goName(column.Name), column})
+		}
+	}
+
+	fmt.Fprintf(w, "// %s is a row of %s.\ntype %s struct {\n", name, row.Parent, name)
+	for _, field := range fields {
+		s := g.resolve(field.obj.Module, *field.obj.Node.Syntax)
+		fmt.Fprintf(w, "\t%s %s\n", field.name, g.goType(field.obj, s))
+	}
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, "// DecodeIndex sets the INDEX fields of the row from the index of an\n")
+	fmt.Fprintf(w, "// instance OID, the part that follows the OID of the column.\n")
+	fmt.Fprintf(w, "func (r *%s) DecodeIndex(index string) error {\n\td := newIndexReader(index)\n", name)
+	for i, obj := range index {
+		s := g.resolve(obj.Module, *obj.Node.Syntax)
+		size := -1
+		if len(s.Sizes) == 1 && s.Sizes[0].Min == s.Sizes[0].Max {
+			size = int(s.Sizes[0].Min)
+		}
+		field := "r." + indexFields[i]
+		switch typ := g.goType(obj, s); typ {
+		case "uint32":
+			fmt.Fprintf(w, "\t%s = d.next()\n", field)
+		case "string":
+			fmt.Fprintf(w, "\t%s = string(d.octets(%d, %t))\n", field, size, implied[i])
+		case "[]byte":
+			fmt.Fprintf(w, "\t%s = d.octets(%d, %t)\n", field, size, implied[i])
+		case "net.IP":
+			fmt.Fprintf(w, "\t%s = net.IP(d.octets(4, false))\n", field)
+		case "OID":
+			fmt.Fprintf(w, "\t%s = d.oid(%t)\n", field, implied[i])
+		default:
+			fmt.Fprintf(w, "\t%s = %s(d.next())\n", field, typ)
+		}
+	}
+	fmt.Fprintf(w, "\treturn d.end(%q)\n}\n\n", row.Name)
+}
+
+// goIndexReader is the source of the type that DecodeIndex methods use to
+// read the index of an instance OID.
+const goIndexReader = `// indexReader reads the values of INDEX objects from the sub-identifiers
+// of an instance index. The first error is kept in err.
+type indexReader struct {
+	ids []uint32
+	err error
+}
+
+func newIndexReader(index string) *indexReader {
+	d := &indexReader{}
+	index = strings.TrimPrefix(index, ".")
+	if index == "" {
+		return d
+	}
+	for _, s := range strings.Split(index, ".") {
+		id, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			d.err = fmt.Errorf("invalid index %q", index)
+			break
+		}
+		d.ids = append(d.ids, uint32(id))
+	}
+	return d
+}
+
+// next returns the next sub-identifier.
+func (d *indexReader) next() uint32 {
+	ids := d.take(1)
+	if len(ids) == 0 {
+		return 0
+	}
+	return ids[0]
+}
+
+// octets returns the next n sub-identifiers as octets. If n is negative,
+// the next sub-identifier gives the number of octets, unless implied is
+// true, in which case the remaining sub-identifiers are the octets.
+func (d *indexReader) octets(n int, implied bool) []byte {
+	ids := d.varying(n, implied)
+	b := make([]byte, len(ids))
+	for i, id := range ids {
+		if id > 255 && d.err == nil {
+			d.err = fmt.Errorf("index sub-identifier %d is not an octet", id)
+		}
+		b[i] = byte(id)
+	}
+	return b
+}
+
+// oid returns the next OID, which is preceded by its length unless implied
+// is true, in which case it is made of the remaining sub-identifiers.
+func (d *indexReader) oid(implied bool) OID {
+	ids := d.varying(-1, implied)
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	return OID(strings.Join(parts, "."))
+}
+
+func (d *indexReader) varying(n int, implied bool) []uint32 {
+	switch {
+	case n >= 0:
+	case implied:
+		n = len(d.ids)
+	default:
+		n = int(d.next())
+	}
+	return d.take(n)
+}
+
+func (d *indexReader) take(n int) []uint32 {
+	if d.err == nil && n > len(d.ids) {
+		d.err = fmt.Errorf("index too short")
+	}
+	if d.err != nil {
+		return nil
+	}
+	ids := d.ids[:n]
+	d.ids = d.ids[n:]
+	return ids
+}
+
+// end returns the first error, or an error if sub-identifiers are left over.
+func (d *indexReader) end(row string) error {
+	if d.err == nil && len(d.ids) > 0 {
+		d.err = fmt.Errorf("index too long")
+	}
+	if d.err != nil {
+		return fmt.Errorf("%s: %v", row, d.err)
+	}
+	return nil
+}
+`
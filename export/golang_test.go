@@ -0,0 +1,193 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package export_test
+
+import (
+	"bytes"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hallidave/mibtool/export"
+	"github.com/hallidave/mibtool/smi"
+)
+
+// checkGo type-checks the source of a generated package.
+func checkGo(t *testing.T, pkgName, src string) *types.Package {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, pkgName+".go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check(pkgName, fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pkg
+}
+
+func TestWriteGo(t *testing.T) {
+	mib := loadMIB(t, "IF-MIB")
+	var buf bytes.Buffer
+	if err := export.WriteGo(&buf, mib, "ifmib", "IF-MIB"); err != nil {
+		t.Fatal(err)
+	}
+	src := buf.String()
+	if !strings.HasPrefix(src, "// Code generated by mib gogen from IF-MIB; DO NOT EDIT.\n\npackage ifmib\n") {
+		t.Errorf("got start\n%s", src[:80])
+	}
+
+	pkg := checkGo(t, "ifmib", src)
+	scope := pkg.Scope()
+	constants := map[string]string{
+		"IfDescrOID":      `"1.3.6.1.2.1.2.2.1.2"`,
+		"LinkDownOID":     `"1.3.6.1.6.3.1.1.5.3"`,
+		"IfAdminStatusUp": "1",
+		"RowStatusActive": "1",
+		"TruthValueFalse": "2",
+	}
+	for name, value := range constants {
+		c, ok := scope.Lookup(name).(*types.Const)
+		if !ok || c.Val().ExactString() != value {
+			t.Errorf("got %s = %v, expected %s", name, scope.Lookup(name), value)
+		}
+	}
+	if typ := scope.Lookup("IfAdminStatus"); typ == nil || types.NewMethodSet(typ.Type()).Lookup(pkg, "String") == nil {
+		t.Errorf("got IfAdminStatus %v without a String method", typ)
+	}
+
+	fields := map[string]string{
+		"IfEntry.IfIndex":                       "int32",
+		"IfEntry.IfDescr":                       "string",
+		"IfEntry.IfAdminStatus":                 "ifmib.IfAdminStatus",
+		"IfEntry.IfPhysAddress":                 "[]byte",
+		"IfEntry.IfSpecific":                    "ifmib.OID",
+		"IfXEntry.IfIndex":                      "int32",
+		"IfXEntry.IfHCInOctets":                 "uint64",
+		"IfRcvAddressEntry.IfRcvAddressAddress": "[]byte",
+		"IfRcvAddressEntry.IfRcvAddressStatus":  "ifmib.RowStatus",
+	}
+	for name, expected := range fields {
+		typeName, fieldName, _ := strings.Cut(name, ".")
+		obj := scope.Lookup(typeName)
+		if obj == nil {
+			t.Errorf("missing type %s", typeName)
+			continue
+		}
+		field, _, _ := types.LookupFieldOrMethod(obj.Type(), true, pkg, fieldName)
+		if field == nil || field.Type().String() != expected {
+			t.Errorf("got field %s %v, expected %s", name, field, expected)
+		}
+		if m, _, _ := types.LookupFieldOrMethod(obj.Type(), true, pkg, "DecodeIndex"); m == nil {
+			t.Errorf("missing %s.DecodeIndex", typeName)
+		}
+	}
+
+	for _, s := range []string{
+		"\tr.IfIndex = int32(d.next())\n\tr.IfRcvAddressAddress = d.octets(-1, false)\n\treturn d.end(\"ifRcvAddressEntry\")\n",
+		"\tcase IfAdminStatusTesting:\n\t\treturn \"testing\"\n\t}\n\treturn fmt.Sprintf(\"IfAdminStatus(%d)\", int(v))\n",
+	} {
+		if !strings.Contains(src, s) {
+			t.Errorf("missing\n%s", s)
+		}
+	}
+}
+
+const zzMIB = `ZZ-MIB DEFINITIONS ::= BEGIN
+IMPORTS OBJECT-TYPE, enterprises FROM SNMPv2-SMI;
+zz OBJECT IDENTIFIER ::= { enterprises 99999 }
+zzTable OBJECT-TYPE
+    SYNTAX SEQUENCE OF ZzEntry
+    MAX-ACCESS not-accessible
+    STATUS current
+    DESCRIPTION "A table indexed by an enumeration."
+    ::= { zz 1 }
+zzEntry OBJECT-TYPE
+    SYNTAX ZzEntry
+    MAX-ACCESS not-accessible
+    STATUS current
+    DESCRIPTION "A row."
+    INDEX { zzKind }
+    ::= { zzTable 1 }
+ZzEntry ::= SEQUENCE { zzKind INTEGER }
+zzKind OBJECT-TYPE
+    SYNTAX INTEGER { small(1), large(2) }
+    MAX-ACCESS not-accessible
+    STATUS current
+    DESCRIPTION "The kind."
+    ::= { zzEntry 1 }
+END
+`
+
+const zzUserMIB = `ZZ-USER-MIB DEFINITIONS ::= BEGIN
+IMPORTS OBJECT-TYPE, Integer32 FROM SNMPv2-SMI
+        zz, zzKind FROM ZZ-MIB;
+zzUserTable OBJECT-TYPE
+    SYNTAX SEQUENCE OF ZzUserEntry
+    MAX-ACCESS not-accessible
+    STATUS current
+    DESCRIPTION "A table indexed by an object of ZZ-MIB."
+    ::= { zz 2 }
+zzUserEntry OBJECT-TYPE
+    SYNTAX ZzUserEntry
+    MAX-ACCESS not-accessible
+    STATUS current
+    DESCRIPTION "A row."
+    INDEX { zzKind, zzUserValue }
+    ::= { zzUserTable 1 }
+ZzUserEntry ::= SEQUENCE { zzUserValue Integer32 }
+zzUserValue OBJECT-TYPE
+    SYNTAX Integer32
+    MAX-ACCESS read-only
+    STATUS current
+    DESCRIPTION "A value."
+    ::= { zzUserEntry 1 }
+END
+`
+
+func TestWriteGoForeignIndex(t *testing.T) {
+	dir := t.TempDir()
+	smiText, err := os.ReadFile(filepath.Join("..", "smi", "testdata", "SNMPv2-SMI"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{"SNMPv2-SMI": smiText, "ZZ-MIB": []byte(zzMIB), "ZZ-USER-MIB": []byte(zzUserMIB)}
+	for name, text := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), text, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mib := smi.NewMIB(dir)
+	if err := mib.LoadModules("ZZ-USER-MIB"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The enumeration of zzKind is only used by the INDEX of zzUserEntry
+	var buf bytes.Buffer
+	if err := export.WriteGo(&buf, mib, "zzuser", "ZZ-USER-MIB"); err != nil {
+		t.Fatal(err)
+	}
+	pkg := checkGo(t, "zzuser", buf.String())
+	obj := pkg.Scope().Lookup("ZzUserEntry")
+	if obj == nil {
+		t.Fatal("missing type ZzUserEntry")
+	}
+	field, _, _ := types.LookupFieldOrMethod(obj.Type(), true, pkg, "ZzKind")
+	if field == nil || field.Type().String() != "zzuser.ZzKind" {
+		t.Errorf("got field ZzKind %v, expected zzuser.ZzKind", field)
+	}
+	if c, ok := pkg.Scope().Lookup("ZzKindLarge").(*types.Const); !ok || c.Val().ExactString() != "2" {
+		t.Errorf("got ZzKindLarge %v, expected 2", pkg.Scope().Lookup("ZzKindLarge"))
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/hallidave/mibtool/smi"
+)
+
+// WriteIdentifiers writes the identifiers defined by each of the modules
+// named by modNames, or by all loaded modules, in a format modelled on
+// smidump -f identifiers. Types are listed first, followed by the symbols in order by
+// OID with their kind and OID.
+func WriteIdentifiers(w io.Writer, mib *smi.MIB, modNames ...string) error {
+	mods, err := loadedModules(mib, modNames)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	for _, mod := range mods {
+		fmt.Fprintf(bw, "# %s list of identifiers (generated by mibtool in the style of smidump %s)\n\n", mod.Name, smidumpVersion)
+		syms := moduleSymbols(mod)
+		types := moduleTypes(mod)
+		width := 0
+		for _, t := range types {
+			width = max(width, len(t.Name))
+		}
+		for _, sym := range syms {
+			width = max(width, len(sym.Name))
+		}
+		for _, t := range types {
+			fmt.Fprintf(bw, "%s %s type\n", mod.Name, pad(t.Name, width))
+		}
+		for _, sym := range syms {
+			fmt.Fprintf(bw, "%s %s %s %s\n", mod.Name, pad(sym.Name, width), pad(nodeKind(sym), 12), symbolOID(sym))
+		}
+		fmt.Fprintln(bw)
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,272 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+// Package export writes the modules loaded in an smi.MIB in formats that
+// other tools can read.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+
+	"github.com/hallidave/mibtool/smi"
+)
+
+// JSONVersion is the version of the JSON document written by WriteJSON. It
+// changes when fields are removed or their meaning changes. Fields may be
+// added without changing the version, so readers should ignore fields they
+// do not know.
+const JSONVersion = 1
+
+// A Document is the JSON document written by WriteJSON. Its fields and those
+// of the types it contains define the JSON schema. Fields for clauses that
+// are missing from a definition are omitted.
+type Document struct {
+	Version int       `json:"version"`
+	Modules []*Module `json:"modules"`
+}
+
+// A Module is a loaded MIB module. Symbols are in order by OID.
+type Module struct {
+	Name    string    `json:"name"`
+	File    string    `json:"file"`
+	Imports []Import  `json:"imports,omitempty"`
+	Symbols []*Symbol `json:"symbols,omitempty"`
+	Types   []*Type   `json:"types,omitempty"`
+}
+
+// An Import lists the symbols imported from a module.
+type Import struct {
+	From    string   `json:"from"`
+	Symbols []string `json:"symbols"`
+}
+
+// A Symbol is a named node of the OID tree defined by a module. OID is in
+// dotted form and Parent is the Module::name of the parent symbol, or its
+// OID if it has no name. NodeType is the macro that defines the symbol, as
+// in OBJECT-TYPE, and Kind is scalar, table, row or column for OBJECT-TYPE
+// symbols. Line is the line of the definition in the module file. Objects
+// lists the OBJECTS of a NOTIFICATION-TYPE.
+type Symbol struct {
+	Name        string   `json:"name"`
+	OID         string   `json:"oid"`
+	Parent      string   `json:"parent,omitempty"`
+	NodeType    string   `json:"nodeType"`
+	Kind        string   `json:"kind,omitempty"`
+	Line        int      `json:"line,omitempty"`
+	Syntax      *Syntax  `json:"syntax,omitempty"`
+	Units       string   `json:"units,omitempty"`
+	Access      string   `json:"access,omitempty"`
+	Status      string   `json:"status,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Reference   string   `json:"reference,omitempty"`
+	Index       []Index  `json:"index,omitempty"`
+	Augments    string   `json:"augments,omitempty"`
+	Objects     []string `json:"objects,omitempty"`
+}
+
+// An Index is an object in the INDEX clause of a table row.
+type Index struct {
+	Name    string `json:"name"`
+	Implied bool   `json:"implied,omitempty"`
+}
+
+// A Syntax is the type of an object or textual convention as written in its
+// definition, with the constraints that refine it.
+type Syntax struct {
+	Type   string        `json:"type"`
+	Enums  []NamedNumber `json:"enums,omitempty"`
+	Ranges []Range       `json:"ranges,omitempty"`
+	Sizes  []Range       `json:"sizes,omitempty"`
+}
+
+// A NamedNumber is a value of an enumeration or a bit of a BITS type.
+type NamedNumber struct {
+	Name  string `json:"name"`
+	Value int64  `json:"value"`
+}
+
+// A Range is a range of values, or of lengths for a SIZE constraint.
+type Range struct {
+	Min int64 `json:"min"`
+	Max int64 `json:"max"`
+}
+
+// A Type is a type defined by a module, either a TEXTUAL-CONVENTION or a
+// type assignment.
+type Type struct {
+	Name              string `json:"name"`
+	Line              int    `json:"line,omitempty"`
+	TextualConvention bool   `json:"textualConvention,omitempty"`
+	DisplayHint       string `json:"displayHint,omitempty"`
+	Status            string `json:"status,omitempty"`
+	Description       string `json:"description,omitempty"`
+	Reference         string `json:"reference,omitempty"`
+	Syntax            Syntax `json:"syntax"`
+}
+
+// NewDocument describes the modules of the MIB named by modNames, or all of
+// the loaded modules in order by name if modNames is empty.
+func NewDocument(mib *smi.MIB, modNames ...string) (*Document, error) {
+	mods, err := loadedModules(mib, modNames)
+	if err != nil {
+		return nil, err
+	}
+	doc := &Document{Version: JSONVersion, Modules: make([]*Module, len(mods))}
+	for i, mod := range mods {
+		doc.Modules[i] = newModule(mod)
+	}
+	return doc, nil
+}
+
+// WriteJSON writes the Document for the modules of the MIB named by modNames,
+// or for all of the loaded modules if modNames is empty.
+func WriteJSON(w io.Writer, mib *smi.MIB, modNames ...string) error {
+	doc, err := NewDocument(mib, modNames...)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// loadedModules returns the modules named by modNames, which may be module
+// aliases, or all of the loaded modules in order by name.
+func loadedModules(mib *smi.MIB, modNames []string) ([]*smi.Module, error) {
+	var mods []*smi.Module
+	if len(modNames) == 0 {
+		for _, mod := range mib.Modules {
+			if mod.IsLoaded {
+				mods = append(mods, mod)
+			}
+		}
+		sort.Slice(mods, func(i, j int) bool {
+			return mods[i].Name < mods[j].Name
+		})
+		return mods, nil
+	}
+	for _, name := range modNames {
+		mod := mib.Module(name)
+		if mod == nil || !mod.IsLoaded {
+			return nil, fmt.Errorf("module %s not loaded", name)
+		}
+		mods = append(mods, mod)
+	}
+	return mods, nil
+}
+
+func newModule(mod *smi.Module) *Module {
+	m := &Module{Name: mod.Name, File: mod.File}
+	for _, imp := range mod.Imports {
+		m.Imports = append(m.Imports, Import{From: imp.From, Symbols: imp.Symbols})
+	}
+	for _, sym := range moduleSymbols(mod) {
+		m.Symbols = append(m.Symbols, newSymbol(sym))
+	}
+	for i := range mod.Types {
+		t := &mod.Types[i]
+		m.Types = append(m.Types, &Type{
+			Name:              t.Name,
+			Line:              t.Line,
+			TextualConvention: t.IsTextualConvention,
+			DisplayHint:       t.DisplayHint,
+			Status:            t.Status,
+			Description:       t.Description,
+			Reference:         t.Reference,
+			Syntax:            newSyntax(t.Syntax),
+		})
+	}
+	return m
+}
+
+// moduleSymbols returns the symbols defined by the module in order by OID.
+func moduleSymbols(mod *smi.Module) []*smi.Symbol {
+	syms := make([]*smi.Symbol, 0, len(mod.Symbols))
+	oids := make(map[*smi.Symbol]smi.OID, len(mod.Symbols))
+	for _, sym := range mod.Symbols {
+		syms = append(syms, sym)
+		oids[sym] = symbolOID(sym)
+	}
+	sort.Slice(syms, func(i, j int) bool {
+		if c := oids[syms[i]].Compare(oids[syms[j]]); c != 0 {
+			return c < 0
+		}
+		return syms[i].Name < syms[j].Name
+	})
+	return syms
+}
+
+// symbolOID returns the OID of sym by following its parents to the root.
+func symbolOID(sym *smi.Symbol) smi.OID {
+	var oid smi.OID
+	for s := sym; s != nil; s = s.Parent {
+		oid = append(oid, s.ID)
+	}
+	slices.Reverse(oid)
+	return oid
+}
+
+func newSymbol(sym *smi.Symbol) *Symbol {
+	s := &Symbol{
+		Name:     sym.Name,
+		OID:      symbolOID(sym).String(),
+		NodeType: sym.Type.String(),
+	}
+	switch {
+	case sym.Parent == nil:
+	case sym.Parent.Name == "":
+		s.Parent = symbolOID(sym.Parent).String()
+	default:
+		s.Parent = sym.Parent.String()
+	}
+	switch {
+	case sym.IsScalar():
+		s.Kind = "scalar"
+	case sym.IsTable():
+		s.Kind = "table"
+	case sym.IsRow():
+		s.Kind = "row"
+	case sym.IsColumn():
+		s.Kind = "column"
+	}
+	node := sym.Node
+	if node == nil {
+		return s
+	}
+	s.Line = node.Line
+	if node.Syntax != nil {
+		syntax := newSyntax(*node.Syntax)
+		s.Syntax = &syntax
+	}
+	s.Units = node.Units
+	s.Access = node.Access
+	s.Status = node.Status
+	s.Description = node.Description
+	s.Reference = node.Reference
+	for _, index := range node.Index {
+		s.Index = append(s.Index, Index{Name: index.Name, Implied: index.Implied})
+	}
+	s.Augments = node.Augments
+	s.Objects = node.Objects
+	return s
+}
+
+func newSyntax(syntax smi.Syntax) Syntax {
+	s := Syntax{Type: syntax.Type}
+	for _, enum := range syntax.Enums {
+		s.Enums = append(s.Enums, NamedNumber{Name: enum.Name, Value: enum.Value})
+	}
+	for _, r := range syntax.Ranges {
+		s.Ranges = append(s.Ranges, Range{Min: r.Min, Max: r.Max})
+	}
+	for _, r := range syntax.Sizes {
+		s.Sizes = append(s.Sizes, Range{Min: r.Min, Max: r.Max})
+	}
+	return s
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package export_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/hallidave/mibtool/export"
+	"github.com/hallidave/mibtool/smi"
+)
+
+func loadMIB(t testing.TB, modNames ...string) *smi.MIB {
+	t.Helper()
+	mib := smi.NewMIB("../smi/testdata")
+	if err := mib.LoadModules(modNames...); err != nil {
+		t.Fatal(err)
+	}
+	return mib
+}
+
+func TestWriteJSON(t *testing.T) {
+	mib := loadMIB(t, "IF-MIB")
+	var buf bytes.Buffer
+	if err := export.WriteJSON(&buf, mib, "IF-MIB"); err != nil {
+		t.Fatal(err)
+	}
+	var doc export.Document
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Version != export.JSONVersion || len(doc.Modules) != 1 {
+		t.Fatalf("got version %d with %d modules", doc.Version, len(doc.Modules))
+	}
+	mod := doc.Modules[0]
+	if mod.Name != "IF-MIB" || len(mod.Imports) == 0 || mod.Imports[0].From != "SNMPv2-SMI" {
+		t.Errorf("got module %s with imports %v", mod.Name, mod.Imports)
+	}
+
+	symbols := make(map[string]*export.Symbol)
+	for _, sym := range mod.Symbols {
+		symbols[sym.Name] = sym
+	}
+	if mod.Symbols[0].Name != "interfaces" || mod.Symbols[0].NodeType != "OBJECT IDENTIFIER" {
+		t.Errorf("got first symbol %+v, expected interfaces", mod.Symbols[0])
+	}
+	if ifMIB := symbols["ifMIB"]; ifMIB.NodeType != "MODULE-IDENTITY" || ifMIB.Description == "" {
+		t.Errorf("got ifMIB %+v", ifMIB)
+	}
+	ifEntry := symbols["ifEntry"]
+	if ifEntry.OID != "1.3.6.1.2.1.2.2.1" || ifEntry.Parent != "IF-MIB::ifTable" || ifEntry.Kind != "row" ||
+		len(ifEntry.Index) != 1 || ifEntry.Index[0].Name != "ifIndex" {
+		t.Errorf("got ifEntry %+v", ifEntry)
+	}
+	if ifXEntry := symbols["ifXEntry"]; ifXEntry.Augments != "ifEntry" {
+		t.Errorf("got ifXEntry %+v", ifXEntry)
+	}
+	status := symbols["ifAdminStatus"]
+	if status.Kind != "column" || status.Access != "read-write" || status.Syntax.Type != "INTEGER" ||
+		len(status.Syntax.Enums) != 3 || status.Syntax.Enums[1] != (export.NamedNumber{Name: "down", Value: 2}) {
+		t.Errorf("got ifAdminStatus %+v with syntax %+v", status, status.Syntax)
+	}
+	if alias := symbols["ifAlias"]; alias.Syntax.Type != "DisplayString" || len(alias.Syntax.Sizes) != 1 ||
+		alias.Syntax.Sizes[0] != (export.Range{Min: 0, Max: 64}) {
+		t.Errorf("got ifAlias syntax %+v", alias.Syntax)
+	}
+
+	var found bool
+	for _, typ := range mod.Types {
+		if typ.Name == "InterfaceIndex" {
+			found = typ.TextualConvention && typ.DisplayHint == "d" && typ.Syntax.Type == "Integer32"
+		}
+	}
+	if !found {
+		t.Errorf("InterfaceIndex textual convention not found in %v", mod.Types)
+	}
+
+	if _, err := export.NewDocument(mib, "RMON-MIB"); err == nil {
+		t.Error("got no error for a module that is not loaded")
+	}
+	doc2, err := export.NewDocument(mib)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc2.Modules) < 4 || doc2.Modules[0].Name != "IANAifType-MIB" {
+		t.Errorf("got %d modules starting with %s", len(doc2.Modules), doc2.Modules[0].Name)
+	}
+}
+
+func TestModuleAlias(t *testing.T) {
+	// RFC1213-MIB is a default alias for SNMPv2-MIB
+	mib := loadMIB(t, "RFC1213-MIB")
+	writers := map[string]func(io.Writer) error{
+		"json": func(w io.Writer) error { return export.WriteJSON(w, mib, "RFC1213-MIB") },
+		"tree": func(w io.Writer) error { return export.WriteTree(w, mib, "RFC1213-MIB") },
+		"yang": func(w io.Writer) error { return export.WriteYANG(w, mib, "RFC1213-MIB") },
+		"go":   func(w io.Writer) error { return export.WriteGo(w, mib, "rfc1213", "RFC1213-MIB") },
+	}
+	for name, write := range writers {
+		var buf bytes.Buffer
+		if err := write(&buf); err != nil {
+			t.Errorf("%s: %v", name, err)
+		} else if !strings.Contains(buf.String(), "SNMPv2-MIB") {
+			t.Errorf("%s: expected output for SNMPv2-MIB", name)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/hallidave/mibtool/smi"
+)
+
+// metricKinds, metricStatuses and metricAccesses are the values counted by
+// WriteMetrics, in the order they are shown.
+var (
+	metricKinds    = []string{"node", "scalar", "table", "row", "column", "notification", "type"}
+	metricStatuses = []string{"current", "deprecated", "obsolete"}
+	metricAccesses = []string{"not-accessible", "accessible-for-notify", "read-only", "read-write", "read-create", "write-only"}
+)
+
+// WriteMetrics writes statistics about each of the modules named by
+// modNames, or about all loaded modules, in a format modelled on smidump -f
+// metrics. The definitions are counted by kind and by status, the scalars
+// and columns by access, and the types used by scalars and columns by how
+// often they are used.
+func WriteMetrics(w io.Writer, mib *smi.MIB, modNames ...string) error {
+	mods, err := loadedModules(mib, modNames)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	for _, mod := range mods {
+		fmt.Fprintf(bw, "# %s metrics (generated by mibtool in the style of smidump %s)\n\n", mod.Name, smidumpVersion)
+		kinds := make(map[string]int)
+		statuses := make(map[string]int)
+		accesses := make(map[string]int)
+		usage := make(map[string]int)
+		for _, t := range moduleTypes(mod) {
+			kinds["type"]++
+			statuses[statusName(t.Status)]++
+		}
+		for _, sym := range moduleSymbols(mod) {
+			kind := nodeKind(sym)
+			kinds[kind]++
+			if sym.Node == nil {
+				continue
+			}
+			if sym.Node.Status != "" {
+				statuses[sym.Node.Status]++
+			}
+			if kind == "scalar" || kind == "column" {
+				accesses[sym.Node.Access]++
+				if sym.Node.Syntax != nil {
+					usage[libsmiTypeName(*sym.Node.Syntax)]++
+				}
+			}
+		}
+		writeCounts(bw, "KIND", metricKinds, kinds)
+		writeCounts(bw, "STATUS", metricStatuses, statuses)
+		writeCounts(bw, "ACCESS", metricAccesses, accesses)
+
+		types := make([]string, 0, len(usage))
+		for name := range usage {
+			types = append(types, name)
+		}
+		sort.Slice(types, func(i, j int) bool {
+			if usage[types[i]] != usage[types[j]] {
+				return usage[types[i]] > usage[types[j]]
+			}
+			return types[i] < types[j]
+		})
+		writeCounts(bw, "TYPE USAGE", types, usage)
+	}
+	return bw.Flush()
+}
+
+// statusName returns the status of a type, which is current for type
+// assignments that have no STATUS clause.
+func statusName(status string) string {
+	if status == "" {
+		return "current"
+	}
+	return status
+}
+
+// writeCounts writes a section of the metrics with the count of each of the
+// names that occurs and their total.
+func writeCounts(w *bufio.Writer, title string, names []string, counts map[string]int) {
+	width := len("total")
+	for _, name := range names {
+		width = max(width, len(name))
+	}
+	fmt.Fprintf(w, "# %s\n", title)
+	total := 0
+	for _, name := range names {
+		if n := counts[name]; n > 0 {
+			fmt.Fprintf(w, "%s %6d\n", pad(name, width), n)
+			total += n
+		}
+	}
+	fmt.Fprintf(w, "%s %6d\n\n", pad("total", width), total)
+}
@@ -0,0 +1,198 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package export
+
+import (
+	"strings"
+
+	"github.com/hallidave/mibtool/smi"
+)
+
+// The smidump formats are modelled on the output of the smidump command of
+// libsmi, written from its documentation and smi.dtd. They have not been
+// compared with the output of smidump itself and may differ from it in
+// layout and detail, so they are approximations rather than drop-in
+// replacements. Conformance definitions, such as OBJECT-GROUP and
+// MODULE-COMPLIANCE, are not kept by the parser and are missing from every
+// format, where smidump would include them.
+
+// smidumpVersion is the libsmi version the smidump formats are modelled on.
+const smidumpVersion = "0.4.8"
+
+// libsmiBaseTypes maps the SMI base types to the names of the libsmi base
+// types they belong to. INTEGER types with named numbers are Enumerations.
+var libsmiBaseTypes = map[string]string{
+	"INTEGER":           "Integer32",
+	"Integer32":         "Integer32",
+	"Unsigned32":        "Unsigned32",
+	"Counter32":         "Unsigned32",
+	"Gauge32":           "Unsigned32",
+	"TimeTicks":         "Unsigned32",
+	"Counter64":         "Unsigned64",
+	"Integer64":         "Integer64",
+	"Unsigned64":        "Unsigned64",
+	"OCTET STRING":      "OctetString",
+	"Opaque":            "OctetString",
+	"IpAddress":         "OctetString",
+	"OBJECT IDENTIFIER": "ObjectIdentifier",
+	"BITS":              "Bits",
+}
+
+// smiTypeModule is the module that defines the application types in libsmi.
+const smiTypeModule = "SNMPv2-SMI"
+
+// libsmiTypeName returns the name libsmi gives the type written in a syntax.
+// The ASN.1 types are named after their base types.
+func libsmiTypeName(syntax smi.Syntax) string {
+	switch syntax.Type {
+	case "INTEGER":
+		if len(syntax.Enums) > 0 {
+			return "Enumeration"
+		}
+		return "Integer32"
+	case "OCTET STRING", "OBJECT IDENTIFIER", "BITS":
+		return libsmiBaseTypes[syntax.Type]
+	}
+	return syntax.Type
+}
+
+// isASN1Type returns true if the type of a syntax is an ASN.1 type rather
+// than a type defined by a module.
+func isASN1Type(name string) bool {
+	switch name {
+	case "INTEGER", "OCTET STRING", "OBJECT IDENTIFIER", "BITS":
+		return true
+	}
+	return false
+}
+
+// typeModule returns the name of the module that defines the type of a
+// syntax used in module mod, or the empty string for the ASN.1 types.
+func typeModule(mib *smi.MIB, mod *smi.Module, syntax smi.Syntax) string {
+	if isASN1Type(syntax.Type) {
+		return ""
+	}
+	if defMod, _ := mib.FindType(mod, syntax.Type); defMod != nil {
+		return defMod.Name
+	}
+	if _, ok := libsmiBaseTypes[syntax.Type]; ok {
+		return smiTypeModule
+	}
+	return ""
+}
+
+// baseType returns the libsmi base type of a syntax used in module mod,
+// following the types it is derived from.
+func baseType(mib *smi.MIB, mod *smi.Module, syntax smi.Syntax) string {
+	enums := len(syntax.Enums) > 0
+	for depth := 0; depth < smi.MaxTypeDepth && mod != nil; depth++ {
+		if base, ok := libsmiBaseTypes[syntax.Type]; ok {
+			if enums && base == "Integer32" {
+				return "Enumeration"
+			}
+			return base
+		}
+		var t *smi.Type
+		mod, t = mib.FindType(mod, syntax.Type)
+		if t == nil {
+			break
+		}
+		syntax = t.Syntax
+		enums = enums || len(syntax.Enums) > 0
+	}
+	return "Unknown"
+}
+
+// moduleTypes returns the types defined by a module, leaving out the
+// SEQUENCE types of table rows, which libsmi does not treat as types.
+func moduleTypes(mod *smi.Module) []*smi.Type {
+	var types []*smi.Type
+	for i := range mod.Types {
+		if t := &mod.Types[i]; t.Syntax.Type != "SEQUENCE" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// nodeTypeName returns the type of a scalar, column or row as smidump shows
+// it, or the empty string for symbols without a type and for tables.
+func nodeTypeName(sym *smi.Symbol) string {
+	if sym.Node == nil || sym.Node.Syntax == nil || sym.IsTable() {
+		return ""
+	}
+	return libsmiTypeName(*sym.Node.Syntax)
+}
+
+// nodeKind returns the libsmi node kind of a symbol.
+func nodeKind(sym *smi.Symbol) string {
+	switch {
+	case sym.IsScalar():
+		return "scalar"
+	case sym.IsTable():
+		return "table"
+	case sym.IsRow():
+		return "row"
+	case sym.IsColumn():
+		return "column"
+	case sym.Type == smi.NodeNotification:
+		return "notification"
+	}
+	return "node"
+}
+
+// statusChar returns the character that marks the status of a node in the
+// smidump trees.
+func statusChar(status string) byte {
+	switch status {
+	case "deprecated":
+		return 'x'
+	case "obsolete":
+		return 'o'
+	}
+	return '+'
+}
+
+// accessFlags returns the access flags shown for a node in the smidump tree.
+func accessFlags(access string) string {
+	switch access {
+	case "accessible-for-notify":
+		return "--n"
+	case "read-only":
+		return "r-n"
+	case "read-write", "read-create", "write-only":
+		return "rwn"
+	}
+	return "---"
+}
+
+// indexNames returns the INDEX objects of a table row as smidump lists them,
+// following AUGMENTS to the row that defines the index. An IMPLIED object is
+// marked with a *.
+func indexNames(mib *smi.MIB, row *smi.Symbol) []string {
+	for depth := 0; depth < smi.MaxTypeDepth && row != nil && row.Node != nil && row.Node.Augments != ""; depth++ {
+		row = mib.LookupSymbol(row.Module, row.Node.Augments)
+	}
+	if row == nil || row.Node == nil {
+		return nil
+	}
+	names := make([]string, len(row.Node.Index))
+	for i, index := range row.Node.Index {
+		names[i] = index.Name
+		if index.Implied {
+			names[i] = "*" + index.Name
+		}
+	}
+	return names
+}
+
+// pad returns s padded with spaces to at least width bytes.
+func pad(s string, width int) string {
+	if len(s) >= width {
+		return s
+	}
+	return s + strings.Repeat(" ", width-len(s))
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package export_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hallidave/mibtool/export"
+	"github.com/hallidave/mibtool/smi"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// TestSmidumpFormats compares the output for IF-MIB with the golden files
+// testdata/IF-MIB.format. Run the test with -update to rewrite them. The
+// golden files are output of this package, not of smidump, so they catch
+// changes to the output but do not show that it matches smidump.
+func TestSmidumpFormats(t *testing.T) {
+	mib := loadMIB(t, "IF-MIB")
+	tests := []struct {
+		format string
+		write  func(io.Writer, *smi.MIB, ...string) error
+	}{
+		{"tree", export.WriteTree},
+		{"identifiers", export.WriteIdentifiers},
+		{"types", export.WriteTypes},
+		{"metrics", export.WriteMetrics},
+		{"xml", export.WriteXML},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		if err := test.write(&buf, mib, "IF-MIB"); err != nil {
+			t.Fatal(err)
+		}
+		golden := filepath.Join("testdata", "IF-MIB."+test.format)
+		if *update {
+			if err := os.WriteFile(golden, buf.Bytes(), 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		expected, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != string(expected) {
+			t.Errorf("%s: output differs from %s%s", test.format, golden, firstDiff(got, string(expected)))
+		}
+	}
+
+	if err := export.WriteTree(io.Discard, mib, "NO-SUCH-MIB"); err == nil {
+		t.Error("expected an error for a module that is not loaded")
+	}
+}
+
+// firstDiff describes the first line that differs between got and expected.
+func firstDiff(got, expected string) string {
+	gotLines := strings.Split(got, "\n")
+	expectedLines := strings.Split(expected, "\n")
+	for i := 0; i < max(len(gotLines), len(expectedLines)); i++ {
+		var g, e string
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if i < len(expectedLines) {
+			e = expectedLines[i]
+		}
+		if g != e {
+			return fmt.Sprintf(" at line %d:\ngot      %q\nexpected %q", i+1, g, e)
+		}
+	}
+	return ""
+}
+
+func TestWriteXML(t *testing.T) {
+	mib := loadMIB(t, "IF-MIB")
+	var buf bytes.Buffer
+	if err := export.WriteXML(&buf, mib, "IF-MIB"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "<?xml version=\"1.0\"?>\n") {
+		t.Errorf("got start %q", buf.String()[:40])
+	}
+	if strings.Contains(buf.String(), "></") {
+		t.Error("got an empty element that is not written as <name/>")
+	}
+
+	var doc struct {
+		Module struct {
+			Name     string `xml:"name,attr"`
+			Identity struct {
+				Node string `xml:"node,attr"`
+			} `xml:"identity"`
+		} `xml:"module"`
+		Tables []struct {
+			Name string `xml:"name,attr"`
+			Row  struct {
+				Name   string `xml:"name,attr"`
+				Create string `xml:"create,attr"`
+				Index  []struct {
+					Name string `xml:"name,attr"`
+				} `xml:"linkage>index"`
+				Augments struct {
+					Name string `xml:"name,attr"`
+				} `xml:"linkage>augments"`
+				Columns []struct {
+					Name   string `xml:"name,attr"`
+					Access string `xml:"access"`
+					Type   struct {
+						Module string `xml:"module,attr"`
+						Name   string `xml:"name,attr"`
+					} `xml:"syntax>type"`
+				} `xml:"column"`
+			} `xml:"row"`
+		} `xml:"nodes>table"`
+		Notifications []struct {
+			Name    string `xml:"name,attr"`
+			Objects []struct {
+				Name string `xml:"name,attr"`
+			} `xml:"objects>object"`
+		} `xml:"notifications>notification"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Module.Name != "IF-MIB" || doc.Module.Identity.Node != "ifMIB" {
+		t.Errorf("got module %+v", doc.Module)
+	}
+	if len(doc.Tables) != 5 {
+		t.Fatalf("got %d tables, expected 5", len(doc.Tables))
+	}
+	ifTable := doc.Tables[0]
+	if ifTable.Name != "ifTable" || ifTable.Row.Name != "ifEntry" || len(ifTable.Row.Index) != 1 ||
+		ifTable.Row.Index[0].Name != "ifIndex" {
+		t.Errorf("got ifTable %+v", ifTable)
+	}
+	ifType := ifTable.Row.Columns[2]
+	if ifType.Name != "ifType" || ifType.Access != "readonly" || ifType.Type.Module != "IANAifType-MIB" ||
+		ifType.Type.Name != "IANAifType" {
+		t.Errorf("got ifType %+v", ifType)
+	}
+	if row := doc.Tables[1].Row; row.Name != "ifXEntry" || row.Augments.Name != "ifEntry" {
+		t.Errorf("got row %+v", row)
+	}
+	if row := doc.Tables[2].Row; row.Name != "ifStackEntry" || row.Create != "true" {
+		t.Errorf("got row %+v", row)
+	}
+	if len(doc.Notifications) != 2 || len(doc.Notifications[0].Objects) != 3 {
+		t.Errorf("got notifications %+v", doc.Notifications)
+	}
+}
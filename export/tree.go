@@ -0,0 +1,136 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hallidave/mibtool/smi"
+)
+
+// WriteTree writes the registration tree of each of the modules named by
+// modNames, or of all loaded modules, in a format modelled on smidump -f
+// tree. The tree of a module starts at the deepest node above all of its definitions.
+// Scalars and columns are shown with their access and type, rows with their
+// INDEX objects and notifications with their OBJECTS.
+func WriteTree(w io.Writer, mib *smi.MIB, modNames ...string) error {
+	mods, err := loadedModules(mib, modNames)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	for _, mod := range mods {
+		fmt.Fprintf(bw, "# %s registration tree (generated by mibtool in the style of smidump %s)\n\n", mod.Name, smidumpVersion)
+		t := &treeWriter{w: bw, mib: mib, mod: mod, pruned: make(map[*smi.Symbol]bool)}
+		root := mib.Root
+		if t.prune(root) {
+			continue
+		}
+		for {
+			kids := t.children(root)
+			if len(kids) != 1 {
+				break
+			}
+			root = kids[0]
+		}
+		t.subtree(root, "", 0)
+		fmt.Fprintln(bw)
+	}
+	return bw.Flush()
+}
+
+// treeWriter writes the registration tree of a module.
+type treeWriter struct {
+	w      *bufio.Writer
+	mib    *smi.MIB
+	mod    *smi.Module
+	pruned map[*smi.Symbol]bool
+}
+
+// prune returns true if neither sym nor any symbol below it is defined by
+// the module.
+func (t *treeWriter) prune(sym *smi.Symbol) bool {
+	if pruned, ok := t.pruned[sym]; ok {
+		return pruned
+	}
+	pruned := sym.Module != t.mod
+	if pruned {
+		for _, child := range sym.ChildByID {
+			if !t.prune(child) {
+				pruned = false
+				break
+			}
+		}
+	}
+	t.pruned[sym] = pruned
+	return pruned
+}
+
+// children returns the children of sym that are shown in the tree.
+func (t *treeWriter) children(sym *smi.Symbol) []*smi.Symbol {
+	var kids []*smi.Symbol
+	for _, child := range sym.Children() {
+		if !t.prune(child) {
+			kids = append(kids, child)
+		}
+	}
+	return kids
+}
+
+// subtree writes the line of sym and the subtrees of its children. The
+// last character of prefix is replaced by the status of sym, and the types
+// of scalars and columns are padded to typeWidth.
+func (t *treeWriter) subtree(sym *smi.Symbol, prefix string, typeWidth int) {
+	linePrefix := prefix
+	if prefix != "" {
+		status := ""
+		if sym.Node != nil {
+			status = sym.Node.Status
+		}
+		linePrefix = prefix[:len(prefix)-1] + string(statusChar(status))
+	}
+	name := sym.Name
+	if name == "" {
+		name = " "
+	}
+	switch kind := nodeKind(sym); {
+	case kind == "scalar" || kind == "column":
+		if typeName := nodeTypeName(sym); typeName != "" {
+			fmt.Fprintf(t.w, "%s-- %s %s %s(%d)\n", linePrefix, accessFlags(sym.Node.Access),
+				pad(typeName, typeWidth), name, sym.ID)
+		}
+	case kind == "row":
+		fmt.Fprintf(t.w, "%s--%s(%d) [%s]\n", linePrefix, name, sym.ID, strings.Join(indexNames(t.mib, sym), ","))
+	case kind == "notification":
+		fmt.Fprintf(t.w, "%s--%s(%d) [%s]\n", linePrefix, name, sym.ID, strings.Join(sym.Node.Objects, ","))
+	case prefix == "":
+		fmt.Fprintf(t.w, "--%s(%s)\n", name, symbolOID(sym))
+	default:
+		fmt.Fprintf(t.w, "%s--%s(%d)\n", linePrefix, name, sym.ID)
+	}
+
+	kids := t.children(sym)
+	childWidth := 9
+	for _, child := range kids {
+		childWidth = max(childWidth, len(nodeTypeName(child)))
+	}
+	lastKind := ""
+	for i, child := range kids {
+		kind := nodeKind(child)
+		if (kind != "scalar" && kind != "column") || kind != lastKind {
+			fmt.Fprintf(t.w, "%s  |\n", prefix)
+		}
+		if len(kids) == 1 || i == len(kids)-1 {
+			t.subtree(child, prefix+"   ", childWidth)
+		} else {
+			t.subtree(child, prefix+"  |", childWidth)
+		}
+		lastKind = kind
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/hallidave/mibtool/smi"
+)
+
+// WriteTypes writes the type derivation tree of each of the modules named by
+// modNames, or of all loaded modules, in a format modelled on smidump -f
+// types. The libsmi base types are the roots of the tree. Each type defined by the
+// module is shown below the type it refines, with its restrictions, named
+// numbers and display hint. Types of other modules are included where types
+// of the module are derived from them.
+func WriteTypes(w io.Writer, mib *smi.MIB, modNames ...string) error {
+	mods, err := loadedModules(mib, modNames)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	for _, mod := range mods {
+		fmt.Fprintf(bw, "# %s type derivation tree (generated by mibtool in the style of smidump %s)\n\n", mod.Name, smidumpVersion)
+		tree := &typeTree{mib: mib, mod: mod, nodes: make(map[string]*typeNode)}
+		for _, t := range moduleTypes(mod) {
+			tree.add(mod, t, 0)
+		}
+		sort.Slice(tree.roots, func(i, j int) bool {
+			return tree.roots[i].name < tree.roots[j].name
+		})
+		for _, root := range tree.roots {
+			root.write(bw, " ")
+			fmt.Fprintln(bw)
+		}
+	}
+	return bw.Flush()
+}
+
+// A typeNode is a type in the derivation tree.
+type typeNode struct {
+	name     string
+	detail   string
+	status   string
+	children []*typeNode
+}
+
+// typeTree builds the derivation tree of the types of a module.
+type typeTree struct {
+	mib   *smi.MIB
+	mod   *smi.Module
+	roots []*typeNode
+	nodes map[string]*typeNode
+}
+
+// add adds the type t defined by module mod and the types it is derived from
+// to the tree and returns its node. The SMI types that SNMPv2-SMI defines,
+// such as TimeTicks, are shown as base types, and derivations deeper than
+// smi.MaxTypeDepth are cut off at a base type.
+func (tree *typeTree) add(mod *smi.Module, t *smi.Type, depth int) *typeNode {
+	key := mod.Name + "::" + t.Name
+	if node := tree.nodes[key]; node != nil {
+		return node
+	}
+	node := &typeNode{name: t.Name, detail: typeDetail(t.Syntax, t.DisplayHint), status: t.Status}
+	if mod != tree.mod {
+		node.name = key
+	}
+	tree.nodes[key] = node
+	var parent *typeNode
+	parentMod, parentType := tree.mib.FindType(mod, t.Syntax.Type)
+	if _, ok := libsmiBaseTypes[t.Syntax.Type]; ok || parentType == nil || depth == smi.MaxTypeDepth {
+		parent = tree.base(t.Syntax)
+	} else {
+		parent = tree.add(parentMod, parentType, depth+1)
+	}
+	parent.children = append(parent.children, node)
+	return node
+}
+
+// base returns the node of the SMI type of a syntax that is not derived from
+// a type defined by a module. The application types are shown below the
+// libsmi base types they belong to.
+func (tree *typeTree) base(syntax smi.Syntax) *typeNode {
+	name := libsmiTypeName(syntax)
+	rootName, ok := libsmiBaseTypes[name]
+	if !ok || name == "Enumeration" {
+		rootName = name
+	}
+	root := tree.nodes[rootName]
+	if root == nil {
+		root = &typeNode{name: rootName}
+		tree.nodes[rootName] = root
+		tree.roots = append(tree.roots, root)
+	}
+	if name == rootName {
+		return root
+	}
+	node := tree.nodes[name]
+	if node == nil {
+		node = &typeNode{name: name}
+		tree.nodes[name] = node
+		root.children = append(root.children, node)
+	}
+	return node
+}
+
+// write writes the line of the type and the subtrees of the types derived
+// from it. The last character of prefix is replaced by the status.
+func (node *typeNode) write(w *bufio.Writer, prefix string) {
+	fmt.Fprintf(w, "%s%c--%s%s\n", prefix[:len(prefix)-1], statusChar(node.status), node.name, node.detail)
+	for i, child := range node.children {
+		fmt.Fprintf(w, "%s  |\n", prefix)
+		if i == len(node.children)-1 {
+			child.write(w, prefix+"   ")
+		} else {
+			child.write(w, prefix+"  |")
+		}
+	}
+}
+
+// typeDetail returns the restrictions, named numbers and display hint of a
+// type as shown in the derivation tree.
+func typeDetail(syntax smi.Syntax, hint string) string {
+	var b strings.Builder
+	if len(syntax.Ranges) > 0 {
+		fmt.Fprintf(&b, " [%s]", smi.FormatRanges(syntax.Ranges))
+	}
+	if len(syntax.Sizes) > 0 {
+		fmt.Fprintf(&b, " [%s]", smi.FormatRanges(syntax.Sizes))
+	}
+	if len(syntax.Enums) > 0 {
+		enums := make([]string, len(syntax.Enums))
+		for i, enum := range syntax.Enums {
+			enums[i] = fmt.Sprintf("%s(%d)", enum.Name, enum.Value)
+		}
+		fmt.Fprintf(&b, " {%s}", strings.Join(enums, ", "))
+	}
+	if hint != "" {
+		fmt.Fprintf(&b, " %q", hint)
+	}
+	return b.String()
+}
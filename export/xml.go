@@ -0,0 +1,303 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/hallidave/mibtool/smi"
+)
+
+const (
+	smiXMLNamespace = "http://www.ibr.cs.tu-bs.de/projects/libsmi/xsd/smi"
+	smiXMLDTD       = "http://www.ibr.cs.tu-bs.de/projects/libsmi/dtd/smi.dtd"
+)
+
+// emptyElement matches an element with no content as encoding/xml writes
+// it, as in <import module="A" name="b"></import>.
+var emptyElement = regexp.MustCompile(`<([\w.:-]+)([^<>]*)></[\w.:-]+>`)
+
+// The xml types describe the elements of the libsmi smi.dtd. Elements of
+// clauses that are missing from a definition are omitted.
+type (
+	xmlSMI struct {
+		XMLName       xml.Name           `xml:"smi"`
+		Namespace     string             `xml:"xmlns,attr"`
+		Module        xmlModule          `xml:"module"`
+		Imports       []xmlImport        `xml:"imports>import,omitempty"`
+		Typedefs      []*xmlTypedef      `xml:"typedefs>typedef,omitempty"`
+		Nodes         []*xmlNode         `xml:"nodes>node,omitempty"`
+		Notifications []*xmlNotification `xml:"notifications>notification,omitempty"`
+	}
+
+	xmlModule struct {
+		Name        string       `xml:"name,attr"`
+		Language    string       `xml:"language,attr"`
+		Description xmlText      `xml:"description,omitempty"`
+		Reference   xmlText      `xml:"reference,omitempty"`
+		Identity    *xmlIdentity `xml:"identity"`
+	}
+
+	xmlIdentity struct {
+		Node string `xml:"node,attr"`
+	}
+
+	xmlImport struct {
+		Module string `xml:"module,attr"`
+		Name   string `xml:"name,attr"`
+	}
+
+	xmlTypedef struct {
+		Name         string           `xml:"name,attr,omitempty"`
+		BaseType     string           `xml:"basetype,attr"`
+		Status       string           `xml:"status,attr,omitempty"`
+		Parent       *xmlImport       `xml:"parent"`
+		Ranges       []xmlRange       `xml:"range"`
+		NamedNumbers []xmlNamedNumber `xml:"namednumber"`
+		Format       string           `xml:"format,omitempty"`
+		Description  xmlText          `xml:"description,omitempty"`
+		Reference    xmlText          `xml:"reference,omitempty"`
+	}
+
+	xmlRange struct {
+		Min int64 `xml:"min,attr"`
+		Max int64 `xml:"max,attr"`
+	}
+
+	xmlNamedNumber struct {
+		Name   string `xml:"name,attr"`
+		Number int64  `xml:"number,attr"`
+	}
+
+	// xmlNode is a node, scalar, table, row or column element, named by
+	// XMLName.
+	xmlNode struct {
+		XMLName     xml.Name
+		Name        string      `xml:"name,attr"`
+		OID         string      `xml:"oid,attr"`
+		Status      string      `xml:"status,attr,omitempty"`
+		Create      string      `xml:"create,attr,omitempty"`
+		Syntax      *xmlSyntax  `xml:"syntax"`
+		Access      string      `xml:"access,omitempty"`
+		Linkage     *xmlLinkage `xml:"linkage"`
+		Format      string      `xml:"format,omitempty"`
+		Units       string      `xml:"units,omitempty"`
+		Description xmlText     `xml:"description,omitempty"`
+		Reference   xmlText     `xml:"reference,omitempty"`
+		Children    []*xmlNode
+	}
+
+	xmlSyntax struct {
+		Type    *xmlImport  `xml:"type"`
+		Typedef *xmlTypedef `xml:"typedef"`
+	}
+
+	xmlLinkage struct {
+		Index    []xmlImport `xml:"index"`
+		Augments *xmlImport  `xml:"augments"`
+	}
+
+	// xmlText is the text of a description or reference, written with its
+	// line breaks.
+	xmlText string
+
+	xmlNotification struct {
+		Name        string      `xml:"name,attr"`
+		OID         string      `xml:"oid,attr"`
+		Status      string      `xml:"status,attr,omitempty"`
+		Objects     []xmlImport `xml:"objects>object,omitempty"`
+		Description xmlText     `xml:"description,omitempty"`
+		Reference   xmlText     `xml:"reference,omitempty"`
+	}
+)
+
+// WriteXML writes each of the modules named by modNames, or all loaded
+// modules, as an XML document following the smi.dtd of libsmi, in a format
+// modelled on smidump -f xml. Tables contain their rows and rows their
+// columns.
+func WriteXML(w io.Writer, mib *smi.MIB, modNames ...string) error {
+	mods, err := loadedModules(mib, modNames)
+	if err != nil {
+		return err
+	}
+	for _, mod := range mods {
+		fmt.Fprintf(w, "<?xml version=\"1.0\"?>\n\n")
+		fmt.Fprintf(w, "<!-- This module has been generated by mibtool in the style of smidump %s. Do not edit. -->\n\n", smidumpVersion)
+		fmt.Fprintf(w, "<!DOCTYPE smi SYSTEM %q>\n\n", smiXMLDTD)
+		var buf bytes.Buffer
+		enc := xml.NewEncoder(&buf)
+		enc.Indent("", "  ")
+		if err := enc.Encode(newXMLSMI(mib, mod)); err != nil {
+			return err
+		}
+		// libsmi writes empty elements as <name/>.
+		out := emptyElement.ReplaceAll(buf.Bytes(), []byte("<$1$2/>"))
+		if _, err := fmt.Fprintf(w, "%s\n", out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t xmlText) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	if err := enc.EncodeToken(xml.CharData(t)); err != nil {
+		return err
+	}
+	return enc.EncodeToken(start.End())
+}
+
+func newXMLSMI(mib *smi.MIB, mod *smi.Module) *xmlSMI {
+	doc := &xmlSMI{
+		Namespace: smiXMLNamespace,
+		Module:    xmlModule{Name: mod.Name, Language: "SMIv2"},
+	}
+	for _, imp := range mod.Imports {
+		for _, name := range imp.Symbols {
+			doc.Imports = append(doc.Imports, xmlImport{Module: imp.From, Name: name})
+		}
+	}
+	for _, t := range moduleTypes(mod) {
+		typedef := newXMLTypedef(mib, mod, t.Syntax)
+		typedef.Name = t.Name
+		typedef.Status = statusName(t.Status)
+		typedef.Format = t.DisplayHint
+		typedef.Description = xmlText(t.Description)
+		typedef.Reference = xmlText(t.Reference)
+		doc.Typedefs = append(doc.Typedefs, typedef)
+	}
+
+	nodes := make(map[*smi.Symbol]*xmlNode)
+	for _, sym := range moduleSymbols(mod) {
+		node := sym.Node
+		if sym.Type == smi.NodeModuleID && node != nil {
+			doc.Module.Identity = &xmlIdentity{Node: sym.Name}
+			doc.Module.Description = xmlText(node.Description)
+			doc.Module.Reference = xmlText(node.Reference)
+		}
+		if sym.Type == smi.NodeNotification {
+			doc.Notifications = append(doc.Notifications, newXMLNotification(mib, sym))
+			continue
+		}
+		x := newXMLNode(mib, sym)
+		nodes[sym] = x
+		if parent := nodes[sym.Parent]; parent != nil && (sym.IsRow() || sym.IsColumn()) {
+			parent.Children = append(parent.Children, x)
+		} else {
+			doc.Nodes = append(doc.Nodes, x)
+		}
+	}
+	return doc
+}
+
+func newXMLNode(mib *smi.MIB, sym *smi.Symbol) *xmlNode {
+	x := &xmlNode{XMLName: xml.Name{Local: nodeKind(sym)}, Name: sym.Name, OID: symbolOID(sym).String()}
+	node := sym.Node
+	if node == nil {
+		return x
+	}
+	x.Status = node.Status
+	x.Description = xmlText(node.Description)
+	x.Reference = xmlText(node.Reference)
+	switch {
+	case sym.IsRow():
+		x.Linkage = &xmlLinkage{}
+		if node.Augments != "" {
+			x.Linkage.Augments = xmlObject(mib, sym.Module, node.Augments)
+		}
+		for _, index := range node.Index {
+			x.Linkage.Index = append(x.Linkage.Index, *xmlObject(mib, sym.Module, index.Name))
+		}
+		for _, column := range sym.ChildByID {
+			if column.Node != nil && column.Node.Access == "read-create" {
+				x.Create = "true"
+			}
+		}
+	case sym.IsScalar() || sym.IsColumn():
+		if node.Syntax != nil {
+			x.Syntax = newXMLSyntax(mib, sym.Module, *node.Syntax)
+		}
+		x.Access = xmlAccess(node.Access)
+		x.Units = node.Units
+	}
+	return x
+}
+
+// newXMLSyntax returns the syntax of an object, which refers to a named type
+// unless the object refines it, in which case the refined type is given as
+// an unnamed typedef.
+func newXMLSyntax(mib *smi.MIB, mod *smi.Module, syntax smi.Syntax) *xmlSyntax {
+	refined := len(syntax.Ranges) > 0 || len(syntax.Sizes) > 0 || len(syntax.Enums) > 0
+	if refined {
+		return &xmlSyntax{Typedef: newXMLTypedef(mib, mod, syntax)}
+	}
+	return &xmlSyntax{Type: &xmlImport{Module: typeModule(mib, mod, syntax), Name: libsmiTypeName(syntax)}}
+}
+
+// newXMLTypedef returns a typedef for a syntax used in module mod, without
+// the clauses that only named types have.
+func newXMLTypedef(mib *smi.MIB, mod *smi.Module, syntax smi.Syntax) *xmlTypedef {
+	typedef := &xmlTypedef{BaseType: baseType(mib, mod, syntax)}
+	if !isASN1Type(syntax.Type) {
+		typedef.Parent = &xmlImport{Module: typeModule(mib, mod, syntax), Name: syntax.Type}
+	}
+	for _, r := range syntax.Ranges {
+		typedef.Ranges = append(typedef.Ranges, xmlRange{Min: r.Min, Max: r.Max})
+	}
+	for _, r := range syntax.Sizes {
+		typedef.Ranges = append(typedef.Ranges, xmlRange{Min: r.Min, Max: r.Max})
+	}
+	for _, enum := range syntax.Enums {
+		typedef.NamedNumbers = append(typedef.NamedNumbers, xmlNamedNumber{Name: enum.Name, Number: enum.Value})
+	}
+	return typedef
+}
+
+func newXMLNotification(mib *smi.MIB, sym *smi.Symbol) *xmlNotification {
+	x := &xmlNotification{Name: sym.Name, OID: symbolOID(sym).String()}
+	if node := sym.Node; node != nil {
+		x.Status = node.Status
+		x.Description = xmlText(node.Description)
+		x.Reference = xmlText(node.Reference)
+		for _, name := range node.Objects {
+			x.Objects = append(x.Objects, *xmlObject(mib, sym.Module, name))
+		}
+	}
+	return x
+}
+
+// xmlObject refers to the object name as seen from module mod, naming the
+// module that defines it.
+func xmlObject(mib *smi.MIB, mod *smi.Module, name string) *xmlImport {
+	if sym := mib.LookupSymbol(mod, name); sym != nil && sym.Module != nil {
+		return &xmlImport{Module: sym.Module.Name, Name: name}
+	}
+	return &xmlImport{Module: mod.Name, Name: name}
+}
+
+// xmlAccess returns the smi.dtd name of a MAX-ACCESS value.
+func xmlAccess(access string) string {
+	switch access {
+	case "":
+		return ""
+	case "accessible-for-notify":
+		return "notifyonly"
+	case "read-only":
+		return "readonly"
+	case "read-write", "read-create":
+		return "readwrite"
+	case "write-only":
+		return "writeonly"
+	}
+	return strings.ReplaceAll(access, "-", "")
+}
@@ -0,0 +1,459 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hallidave/mibtool/smi"
+)
+
+// The modules imported by translated YANG modules, by prefix.
+var yangImports = map[string]string{
+	"smiv2": "ietf-yang-smiv2",
+	"yang":  "ietf-yang-types",
+	"inet":  "ietf-inet-types",
+}
+
+// yangBaseTypes maps the SMI base types to YANG types as in section 4.2 of
+// RFC 6643. INTEGER types with named numbers are enumerations.
+var yangBaseTypes = map[string]string{
+	"INTEGER":           "int32",
+	"Integer32":         "int32",
+	"Unsigned32":        "uint32",
+	"Counter32":         "yang:counter32",
+	"Gauge32":           "yang:gauge32",
+	"TimeTicks":         "yang:timeticks",
+	"Counter64":         "yang:counter64",
+	"OCTET STRING":      "binary",
+	"Opaque":            "binary",
+	"IpAddress":         "inet:ipv4-address",
+	"OBJECT IDENTIFIER": "yang:object-identifier-128",
+	"BITS":              "bits",
+}
+
+// yangTextualConventions maps the textual conventions of SNMPv2-TC that have
+// an equivalent in ietf-yang-types to that type, as RFC 6643 recommends.
+var yangTextualConventions = map[string]string{
+	"PhysAddress": "yang:phys-address",
+	"MacAddress":  "yang:mac-address",
+	"TimeStamp":   "yang:timestamp",
+}
+
+// WriteYANG writes the YANG module that translates the loaded module modName
+// following RFC 6643. Textual conventions become typedefs, scalars become
+// leafs in a container named after their parent node and tables become
+// containers holding a list keyed by the INDEX objects. All data nodes are
+// inside a container named after the module and are config false. OIDs,
+// MAX-ACCESS and DISPLAY-HINT clauses are kept with the extension
+// statements of ietf-yang-smiv2, and the other OID assignments become
+// smiv2:alias statements. Types of other modules are imported from their
+// translations, named after the MIB module. DEFVAL clauses and conformance
+// definitions are not translated, as the smi package does not keep them.
+func WriteYANG(w io.Writer, mib *smi.MIB, modName string) error {
+	mods, err := loadedModules(mib, []string{modName})
+	if err != nil {
+		return err
+	}
+	y := &yangWriter{mib: mib, mod: mods[0], imports: map[string]string{"smiv2": yangImports["smiv2"]}}
+	body := y.body()
+
+	bw := bufio.NewWriter(w)
+	mod := y.mod
+	fmt.Fprintf(bw, "module %s {\n\n", mod.Name)
+	fmt.Fprintf(bw, "  namespace \"urn:ietf:params:xml:ns:yang:smiv2:%s\";\n", mod.Name)
+	fmt.Fprintf(bw, "  prefix %q;\n\n", yangPrefix(mod.Name))
+	prefixes := make([]string, 0, len(y.imports))
+	for prefix := range y.imports {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Slice(prefixes, func(i, j int) bool {
+		return y.imports[prefixes[i]] < y.imports[prefixes[j]]
+	})
+	for _, prefix := range prefixes {
+		fmt.Fprintf(bw, "  import %s {\n    prefix %q;\n  }\n\n", y.imports[prefix], prefix)
+	}
+	bw.Write(bytes.TrimRight(body, "\n"))
+	fmt.Fprintf(bw, "\n}\n")
+	return bw.Flush()
+}
+
+// yangPrefix returns the prefix of the YANG translation of a MIB module.
+func yangPrefix(modName string) string {
+	return strings.ToLower(modName)
+}
+
+// yangString returns s as a quoted YANG string.
+func yangString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// yangWriter translates a module, recording the modules its definitions
+// refer to.
+type yangWriter struct {
+	mib     *smi.MIB
+	mod     *smi.Module
+	imports map[string]string
+	buf     bytes.Buffer
+	indent  int
+}
+
+// line writes a statement at the current indentation.
+func (y *yangWriter) line(format string, args ...interface{}) {
+	y.buf.WriteString(strings.Repeat("  ", y.indent))
+	fmt.Fprintf(&y.buf, format, args...)
+	y.buf.WriteByte('\n')
+}
+
+// open writes a statement that starts a block.
+func (y *yangWriter) open(format string, args ...interface{}) {
+	y.line(format+" {", args...)
+	y.indent++
+}
+
+// close ends the innermost block.
+func (y *yangWriter) close() {
+	y.indent--
+	y.line("}")
+}
+
+// use records that the prefix of a type or path refers to the module
+// modName and returns the prefix.
+func (y *yangWriter) use(prefix, modName string) string {
+	y.imports[prefix] = modName
+	return prefix
+}
+
+// body returns the statements of the module after its imports.
+func (y *yangWriter) body() []byte {
+	mod := y.mod
+	y.indent = 1
+	syms := moduleSymbols(mod)
+	for _, sym := range syms {
+		if sym.Type == smi.NodeModuleID && sym.Node != nil {
+			y.line("description %s;", yangString(sym.Node.Description))
+			y.buf.WriteByte('\n')
+		}
+	}
+	for _, sym := range syms {
+		if sym.Type == smi.NodeModuleID || sym.Type == smi.NodeObjectID {
+			y.open("smiv2:alias %q", sym.Name)
+			y.line("smiv2:oid %q;", symbolOID(sym).String())
+			y.close()
+			y.buf.WriteByte('\n')
+		}
+	}
+	for _, t := range moduleTypes(mod) {
+		y.typedef(t)
+	}
+
+	var groups []*smi.Symbol
+	scalars := make(map[*smi.Symbol][]*smi.Symbol)
+	for _, sym := range syms {
+		switch {
+		case sym.IsScalar():
+			if scalars[sym.Parent] == nil {
+				groups = append(groups, sym.Parent)
+			}
+			scalars[sym.Parent] = append(scalars[sym.Parent], sym)
+		case sym.IsTable():
+			groups = append(groups, sym)
+		}
+	}
+	if len(groups) > 0 {
+		y.open("container %s", mod.Name)
+		y.line("config false;")
+		for _, group := range groups {
+			y.buf.WriteByte('\n')
+			if group.IsTable() {
+				y.table(group)
+				continue
+			}
+			y.open("container %s", group.Name)
+			y.line("smiv2:oid %q;", symbolOID(group).String())
+			for _, sym := range scalars[group] {
+				y.buf.WriteByte('\n')
+				y.leaf(sym)
+			}
+			y.close()
+		}
+		y.close()
+		y.buf.WriteByte('\n')
+	}
+
+	for _, sym := range syms {
+		if sym.Type == smi.NodeNotification {
+			y.notification(sym)
+		}
+	}
+	return y.buf.Bytes()
+}
+
+// typedef writes the typedef of a type defined by the module.
+func (y *yangWriter) typedef(t *smi.Type) {
+	y.open("typedef %s", t.Name)
+	y.typeStmt(y.mod, t.Syntax, t.DisplayHint)
+	if t.Status != "" && t.Status != "current" {
+		y.line("status %s;", t.Status)
+	}
+	if t.Description != "" {
+		y.line("description %s;", yangString(t.Description))
+	}
+	if t.Reference != "" {
+		y.line("reference %s;", yangString(t.Reference))
+	}
+	if t.DisplayHint != "" {
+		y.line("smiv2:display-hint %q;", t.DisplayHint)
+	}
+	y.close()
+	y.buf.WriteByte('\n')
+}
+
+// table writes the container of a table and the list of its rows.
+func (y *yangWriter) table(table *smi.Symbol) {
+	y.open("container %s", table.Name)
+	y.nodeClauses(table)
+	y.line("smiv2:oid %q;", symbolOID(table).String())
+	var row *smi.Symbol
+	for _, child := range table.Children() {
+		if child.IsRow() {
+			row = child
+			break
+		}
+	}
+	if row != nil {
+		y.buf.WriteByte('\n')
+		y.row(row)
+	}
+	y.close()
+}
+
+// row writes the list of a table row. Its keys are the INDEX objects of the
+// row, or of the row it augments. INDEX objects that are not columns of the
+// row are leafrefs to the objects; as in section 11 of RFC 6643, these are
+// all that relate an augmenting row to the row it augments. An INDEX object
+// may occur more than once, as in RMON2-MIB, so the keys of repeated objects
+// are numbered by their position.
+func (y *yangWriter) row(row *smi.Symbol) {
+	y.open("list %s", row.Name)
+	names := indexNames(y.mib, row)
+	objects := make([]string, len(names))
+	keys := make([]string, len(names))
+	implied := ""
+	for i, name := range names {
+		objects[i] = strings.TrimPrefix(name, "*")
+		keys[i] = objects[i]
+		if slices.Index(objects, objects[i]) < i {
+			keys[i] += strconv.Itoa(i + 1)
+		}
+		if objects[i] != name {
+			implied = keys[i]
+		}
+	}
+	y.line("key %q;", strings.Join(keys, " "))
+	y.nodeClauses(row)
+	if implied != "" {
+		y.line("smiv2:implied %q;", implied)
+	}
+	y.line("smiv2:oid %q;", symbolOID(row).String())
+	for i, key := range keys {
+		if _, ok := row.ChildByLabel[key]; ok {
+			continue
+		}
+		y.buf.WriteByte('\n')
+		y.open("leaf %s", key)
+		if obj := y.mib.LookupSymbol(row.Module, objects[i]); obj != nil {
+			y.open("type leafref")
+			y.line("path %q;", y.path(obj))
+			y.close()
+		}
+		y.close()
+	}
+	for _, column := range row.Children() {
+		if column.IsColumn() {
+			y.buf.WriteByte('\n')
+			y.leaf(column)
+		}
+	}
+	y.close()
+}
+
+// leaf writes the leaf of a scalar or column.
+func (y *yangWriter) leaf(sym *smi.Symbol) {
+	y.open("leaf %s", sym.Name)
+	node := sym.Node
+	if node.Syntax != nil {
+		y.typeStmt(sym.Module, *node.Syntax, "")
+	}
+	if node.Units != "" {
+		y.line("units %s;", yangString(node.Units))
+	}
+	y.nodeClauses(sym)
+	if node.Access != "" {
+		y.line("smiv2:max-access %q;", node.Access)
+	}
+	y.line("smiv2:oid %q;", symbolOID(sym).String())
+	y.close()
+}
+
+// nodeClauses writes the status, description and reference of a node.
+func (y *yangWriter) nodeClauses(sym *smi.Symbol) {
+	node := sym.Node
+	if node == nil {
+		return
+	}
+	if node.Status != "" && node.Status != "current" {
+		y.line("status %s;", node.Status)
+	}
+	if node.Description != "" {
+		y.line("description %s;", yangString(node.Description))
+	}
+	if node.Reference != "" {
+		y.line("reference %s;", yangString(node.Reference))
+	}
+}
+
+// notification writes a notification. As in section 12 of RFC 6643, each of
+// its OBJECTS is a container named object-N that holds leafrefs to the
+// object and, for columns, to the INDEX objects of its row.
+func (y *yangWriter) notification(sym *smi.Symbol) {
+	y.open("notification %s", sym.Name)
+	y.nodeClauses(sym)
+	y.line("smiv2:oid %q;", symbolOID(sym).String())
+	for i, name := range sym.Node.Objects {
+		obj := y.mib.LookupSymbol(sym.Module, name)
+		if obj == nil {
+			continue
+		}
+		y.buf.WriteByte('\n')
+		y.open("container object-%d", i+1)
+		if obj.IsColumn() {
+			for _, index := range indexNames(y.mib, obj.Parent) {
+				index = strings.TrimPrefix(index, "*")
+				if indexObj := y.mib.LookupSymbol(obj.Module, index); indexObj != nil && indexObj != obj {
+					y.leafref(indexObj)
+				}
+			}
+		}
+		y.leafref(obj)
+		y.close()
+	}
+	y.close()
+	y.buf.WriteByte('\n')
+}
+
+// leafref writes a leaf that refers to the leaf of an object.
+func (y *yangWriter) leafref(obj *smi.Symbol) {
+	y.open("leaf %s", obj.Name)
+	y.open("type leafref")
+	y.line("path %q;", y.path(obj))
+	y.close()
+	y.close()
+}
+
+// path returns the absolute path of the leaf of a scalar or column.
+func (y *yangWriter) path(obj *smi.Symbol) string {
+	prefix := y.pathPrefix(obj)
+	parts := []string{obj.Module.Name, obj.Name}
+	switch {
+	case obj.IsColumn():
+		parts = []string{obj.Module.Name, obj.Parent.Parent.Name, obj.Parent.Name, obj.Name}
+	case obj.Parent != nil:
+		parts = []string{obj.Module.Name, obj.Parent.Name, obj.Name}
+	}
+	return "/" + prefix + strings.Join(parts, "/"+prefix)
+}
+
+// pathPrefix returns the prefix, with its colon, of the nodes of the module
+// that defines obj.
+func (y *yangWriter) pathPrefix(obj *smi.Symbol) string {
+	if obj.Module == y.mod {
+		return yangPrefix(y.mod.Name) + ":"
+	}
+	return y.use(yangPrefix(obj.Module.Name), obj.Module.Name) + ":"
+}
+
+// typeStmt writes the type statement for a syntax used in module mod. The
+// display hint of a typedef selects string rather than binary for an OCTET
+// STRING that holds text.
+func (y *yangWriter) typeStmt(mod *smi.Module, syntax smi.Syntax, hint string) {
+	var name string
+	var restrictions []string
+	switch {
+	case len(syntax.Enums) > 0 && syntax.Type != "BITS" && baseType(y.mib, mod, syntax) == "Enumeration":
+		name = "enumeration"
+		for _, enum := range syntax.Enums {
+			restrictions = append(restrictions, fmt.Sprintf("enum %s {\n  value %d;\n}", enum.Name, enum.Value))
+		}
+	case len(syntax.Enums) > 0:
+		name = "bits"
+		for _, bit := range syntax.Enums {
+			restrictions = append(restrictions, fmt.Sprintf("bit %s {\n  position %d;\n}", bit.Name, bit.Value))
+		}
+	default:
+		name = y.typeName(mod, syntax, hint)
+		if len(syntax.Ranges) > 0 {
+			restrictions = append(restrictions, fmt.Sprintf("range %q;", smi.FormatRanges(syntax.Ranges)))
+		}
+		if len(syntax.Sizes) > 0 {
+			restrictions = append(restrictions, fmt.Sprintf("length %q;", smi.FormatRanges(syntax.Sizes)))
+		}
+	}
+	if len(restrictions) == 0 {
+		y.line("type %s;", name)
+		return
+	}
+	y.open("type %s", name)
+	for _, r := range restrictions {
+		for _, l := range strings.Split(r, "\n") {
+			y.line("%s", l)
+		}
+	}
+	y.close()
+}
+
+// typeName returns the name of the YANG type for the type of a syntax used
+// in module mod, without its restrictions.
+func (y *yangWriter) typeName(mod *smi.Module, syntax smi.Syntax, hint string) string {
+	if yangType, ok := yangBaseTypes[syntax.Type]; ok {
+		if yangType == "binary" && syntax.Type == "OCTET STRING" && isTextHint(hint) {
+			return "string"
+		}
+		if prefix, _, ok := strings.Cut(yangType, ":"); ok {
+			y.use(prefix, yangImports[prefix])
+		}
+		return yangType
+	}
+	defMod, t := y.mib.FindType(mod, syntax.Type)
+	if t == nil {
+		return "binary"
+	}
+	if defMod.Name == "SNMPv2-TC" {
+		if yangType, ok := yangTextualConventions[t.Name]; ok {
+			y.use("yang", yangImports["yang"])
+			return yangType
+		}
+	}
+	if defMod == y.mod {
+		return t.Name
+	}
+	return y.use(yangPrefix(defMod.Name), defMod.Name) + ":" + t.Name
+}
+
+// isTextHint returns true if an OCTET STRING display hint shows the octets
+// as text, as in 255a or 255t.
+func isTextHint(hint string) bool {
+	hint = strings.TrimLeft(hint, "*0123456789")
+	return hint == "a" || hint == "t"
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package export_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/hallidave/mibtool/export"
+)
+
+func TestWriteYANG(t *testing.T) {
+	mib := loadMIB(t, "IF-MIB")
+	var buf bytes.Buffer
+	if err := export.WriteYANG(&buf, mib, "IF-MIB"); err != nil {
+		t.Fatal(err)
+	}
+	yang := buf.String()
+	expected := []string{
+		"module IF-MIB {\n\n  namespace \"urn:ietf:params:xml:ns:yang:smiv2:IF-MIB\";\n  prefix \"if-mib\";\n",
+		"  import IANAifType-MIB {\n    prefix \"ianaiftype-mib\";\n  }\n",
+		"  import ietf-yang-smiv2 {\n    prefix \"smiv2\";\n  }\n",
+		"  smiv2:alias \"ifMIB\" {\n    smiv2:oid \"1.3.6.1.2.1.31\";\n  }\n",
+		"  typedef OwnerString {\n    type string {\n      length \"0..255\";\n    }\n    status deprecated;\n",
+		"  typedef InterfaceIndex {\n    type int32 {\n      range \"1..2147483647\";\n    }\n",
+		"  container IF-MIB {\n    config false;\n\n    container interfaces {\n      smiv2:oid \"1.3.6.1.2.1.2\";\n\n      leaf ifNumber {\n        type int32;\n",
+		"      list ifEntry {\n        key \"ifIndex\";\n",
+		"        leaf ifIndex {\n          type InterfaceIndex;\n",
+		"        leaf ifType {\n          type ianaiftype-mib:IANAifType;\n",
+		"          type enumeration {\n            enum up {\n              value 1;\n            }\n",
+		"          smiv2:max-access \"read-write\";\n          smiv2:oid \"1.3.6.1.2.1.2.2.1.7\";\n",
+		"        smiv2:oid \"1.3.6.1.2.1.31.1.1.1\";\n\n" +
+			"        leaf ifIndex {\n          type leafref {\n" +
+			"            path \"/if-mib:IF-MIB/if-mib:ifTable/if-mib:ifEntry/if-mib:ifIndex\";\n",
+		"      list ifStackEntry {\n        key \"ifStackHigherLayer ifStackLowerLayer\";\n",
+		"        leaf ifHCInOctets {\n          type yang:counter64;\n",
+		"  notification linkDown {\n",
+		"    container object-3 {\n      leaf ifIndex {\n",
+	}
+	for _, s := range expected {
+		if !strings.Contains(yang, s) {
+			t.Errorf("missing\n%s", s)
+		}
+	}
+	// RFC 6643 defines no extension for AUGMENTS
+	if strings.Contains(yang, "smiv2:augments") {
+		t.Error("unexpected smiv2:augments statement")
+	}
+
+	if err := export.WriteYANG(&buf, mib, "NO-SUCH-MIB"); err == nil {
+		t.Error("expected an error for a module that is not loaded")
+	}
+}
+
+func TestWriteYANGRepeatedIndex(t *testing.T) {
+	mib := loadMIB(t, "RMON2-MIB")
+	var buf bytes.Buffer
+	if err := export.WriteYANG(&buf, mib, "RMON2-MIB"); err != nil {
+		t.Fatal(err)
+	}
+	yang := buf.String()
+
+	// alHostEntry and alMatrixSDEntry use protocolDirLocalIndex twice
+	expected := []string{
+		"      list alHostEntry {\n        key \"hlHostControlIndex alHostTimeMark protocolDirLocalIndex nlHostAddress protocolDirLocalIndex5\";\n",
+		"        leaf protocolDirLocalIndex5 {\n          type leafref {\n" +
+			"            path \"/rmon2-mib:RMON2-MIB/rmon2-mib:protocolDirTable/rmon2-mib:protocolDirEntry/rmon2-mib:protocolDirLocalIndex\";\n",
+		"      list alMatrixSDEntry {\n        key \"hlMatrixControlIndex alMatrixSDTimeMark protocolDirLocalIndex nlMatrixSDSourceAddress nlMatrixSDDestAddress protocolDirLocalIndex6\";\n",
+	}
+	for _, s := range expected {
+		if !strings.Contains(yang, s) {
+			t.Errorf("missing\n%s", s)
+		}
+	}
+
+	// Each list has one leaf per key
+	for _, list := range strings.Split(yang, "    list ")[1:] {
+		seen := make(map[string]bool)
+		for _, line := range strings.Split(list, "\n") {
+			name, ok := strings.CutPrefix(strings.TrimSpace(line), "leaf ")
+			if !ok {
+				continue
+			}
+			if seen[name] {
+				t.Errorf("duplicate %s in list %s", line, strings.Fields(list)[0])
+			}
+			seen[name] = true
+		}
+	}
+}
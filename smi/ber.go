@@ -0,0 +1,426 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"unicode/utf8"
+)
+
+// ValueType identifies the type of an SNMP value. The ValueType values are
+// the BER tags used to encode the values.
+type ValueType byte
+
+// ValueType values for the SMI base types and the SNMP exception values
+const (
+	ValueInteger        ValueType = 0x02
+	ValueOctetString    ValueType = 0x04
+	ValueNull           ValueType = 0x05
+	ValueObjectID       ValueType = 0x06
+	ValueIPAddress      ValueType = 0x40
+	ValueCounter32      ValueType = 0x41
+	ValueGauge32        ValueType = 0x42
+	ValueTimeTicks      ValueType = 0x43
+	ValueOpaque         ValueType = 0x44
+	ValueCounter64      ValueType = 0x46
+	ValueNoSuchObject   ValueType = 0x80
+	ValueNoSuchInstance ValueType = 0x81
+	ValueEndOfMibView   ValueType = 0x82
+)
+
+var valueTypeNames = map[ValueType]string{
+	ValueInteger:        "INTEGER",
+	ValueOctetString:    "OCTET STRING",
+	ValueNull:           "NULL",
+	ValueObjectID:       "OBJECT IDENTIFIER",
+	ValueIPAddress:      "IpAddress",
+	ValueCounter32:      "Counter32",
+	ValueGauge32:        "Gauge32",
+	ValueTimeTicks:      "TimeTicks",
+	ValueOpaque:         "Opaque",
+	ValueCounter64:      "Counter64",
+	ValueNoSuchObject:   "noSuchObject",
+	ValueNoSuchInstance: "noSuchInstance",
+	ValueEndOfMibView:   "endOfMibView",
+}
+
+// syntaxValueTypes are the ValueTypes of the values of the SMI base types.
+var syntaxValueTypes = map[string]ValueType{
+	"INTEGER":           ValueInteger,
+	"Integer32":         ValueInteger,
+	"OCTET STRING":      ValueOctetString,
+	"BITS":              ValueOctetString,
+	"OBJECT IDENTIFIER": ValueObjectID,
+	"IpAddress":         ValueIPAddress,
+	"Counter32":         ValueCounter32,
+	"Gauge32":           ValueGauge32,
+	"Unsigned32":        ValueGauge32,
+	"TimeTicks":         ValueTimeTicks,
+	"Opaque":            ValueOpaque,
+	"Counter64":         ValueCounter64,
+}
+
+func (t ValueType) String() string {
+	if name, ok := valueTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("ValueType(0x%02x)", byte(t))
+}
+
+// IsException returns true for the noSuchObject, noSuchInstance and
+// endOfMibView exception values that an agent returns in place of a value.
+func (t ValueType) IsException() bool {
+	return t == ValueNoSuchObject || t == ValueNoSuchInstance || t == ValueEndOfMibView
+}
+
+// A Value is an SNMP value. Which of the fields holds the value depends on
+// the Type: Int for INTEGER; Uint for Counter32, Gauge32, TimeTicks and
+// Counter64; Bytes for OCTET STRING, IpAddress and Opaque; and OID for
+// OBJECT IDENTIFIER. NULL and the exception values have no content.
+type Value struct {
+	Type  ValueType
+	Int   int64
+	Uint  uint64
+	Bytes []byte
+	OID   OID
+}
+
+func (v Value) String() string {
+	switch v.Type {
+	case ValueInteger:
+		return strconv.FormatInt(v.Int, 10)
+	case ValueCounter32, ValueGauge32, ValueTimeTicks, ValueCounter64:
+		return strconv.FormatUint(v.Uint, 10)
+	case ValueOctetString, ValueOpaque:
+		if utf8.Valid(v.Bytes) && isPrintable(v.Bytes) {
+			return strconv.Quote(string(v.Bytes))
+		}
+		return hex.EncodeToString(v.Bytes)
+	case ValueIPAddress:
+		if len(v.Bytes) == net.IPv4len {
+			return net.IP(v.Bytes).String()
+		}
+		return hex.EncodeToString(v.Bytes)
+	case ValueObjectID:
+		return v.OID.String()
+	}
+	return v.Type.String()
+}
+
+// plainValue converts a Value to the Go value used for its type elsewhere in
+// the package: an int64 for INTEGER, Counter32, Gauge32 and TimeTicks, a
+// uint64 for Counter64, a []byte for OCTET STRING, Opaque and IpAddress, an
+// OID for OBJECT IDENTIFIER and nil for NULL. Exceptions and values that are
+// not a Value are returned unchanged.
+func plainValue(value interface{}) interface{} {
+	v, ok := value.(Value)
+	if !ok {
+		return value
+	}
+	switch v.Type {
+	case ValueInteger:
+		return v.Int
+	case ValueCounter32, ValueGauge32, ValueTimeTicks:
+		return int64(v.Uint)
+	case ValueCounter64:
+		return v.Uint
+	case ValueOctetString, ValueOpaque, ValueIPAddress:
+		return v.Bytes
+	case ValueObjectID:
+		return v.OID
+	case ValueNull:
+		return nil
+	}
+	return value
+}
+
+func isPrintable(b []byte) bool {
+	for _, c := range b {
+		if (c < ' ' || c > '~') && c != '\t' && c != '\r' && c != '\n' {
+			return false
+		}
+	}
+	return true
+}
+
+// EncodeBER returns the BER encoding of the object ID. The object ID must
+// have at least two sub-identifiers, and the first two must be valid for
+// BER encoding (0, 1 or 2 followed by a number less than 40 for 0 and 1).
+func (oid OID) EncodeBER() ([]byte, error) {
+	return EncodeValue(Value{Type: ValueObjectID, OID: oid})
+}
+
+// DecodeOID decodes a BER encoded object ID from the start of b. It
+// returns the object ID and the number of bytes that were read.
+func DecodeOID(b []byte) (OID, int, error) {
+	v, n, err := DecodeValue(b)
+	if err != nil {
+		return nil, 0, err
+	}
+	if v.Type != ValueObjectID {
+		return nil, 0, fmt.Errorf("ber: expected %v, got %v", ValueObjectID, v.Type)
+	}
+	return v.OID, n, nil
+}
+
+// EncodeValue returns the BER encoding of the value.
+func EncodeValue(v Value) ([]byte, error) {
+	var content []byte
+	switch v.Type {
+	case ValueInteger:
+		content = encodeInt(v.Int)
+	case ValueCounter32, ValueGauge32, ValueTimeTicks:
+		if v.Uint > MaxSubID {
+			return nil, fmt.Errorf("ber: %v value out of range: %d", v.Type, v.Uint)
+		}
+		content = encodeUint(v.Uint)
+	case ValueCounter64:
+		content = encodeUint(v.Uint)
+	case ValueOctetString, ValueOpaque:
+		content = v.Bytes
+	case ValueIPAddress:
+		if len(v.Bytes) != net.IPv4len {
+			return nil, fmt.Errorf("ber: IpAddress must be 4 bytes, got %d", len(v.Bytes))
+		}
+		content = v.Bytes
+	case ValueObjectID:
+		var err error
+		content, err = encodeOID(v.OID)
+		if err != nil {
+			return nil, err
+		}
+	case ValueNull, ValueNoSuchObject, ValueNoSuchInstance, ValueEndOfMibView:
+	default:
+		return nil, fmt.Errorf("ber: cannot encode %v", v.Type)
+	}
+	b := append([]byte{byte(v.Type)}, encodeLength(len(content))...)
+	return append(b, content...), nil
+}
+
+// DecodeValue decodes a BER encoded value from the start of b. It returns
+// the value and the number of bytes that were read. Only the definite
+// length form is supported.
+func DecodeValue(b []byte) (Value, int, error) {
+	if len(b) < 2 {
+		return Value{}, 0, fmt.Errorf("ber: truncated value")
+	}
+	t := ValueType(b[0])
+	length, n, err := decodeLength(b[1:])
+	if err != nil {
+		return Value{}, 0, err
+	}
+	start := 1 + n
+	if len(b)-start < length {
+		return Value{}, 0, fmt.Errorf("ber: %v length %d exceeds %d remaining bytes", t, length, len(b)-start)
+	}
+	content := b[start : start+length]
+
+	v := Value{Type: t}
+	switch t {
+	case ValueInteger:
+		v.Int, err = decodeInt(content)
+	case ValueCounter32, ValueGauge32, ValueTimeTicks:
+		v.Uint, err = decodeUint(content, 32)
+	case ValueCounter64:
+		v.Uint, err = decodeUint(content, 64)
+	case ValueOctetString, ValueOpaque:
+		v.Bytes = append([]byte{}, content...)
+	case ValueIPAddress:
+		if len(content) != net.IPv4len {
+			return Value{}, 0, fmt.Errorf("ber: IpAddress must be 4 bytes, got %d", len(content))
+		}
+		v.Bytes = append([]byte{}, content...)
+	case ValueObjectID:
+		v.OID, err = decodeOID(content)
+	case ValueNull, ValueNoSuchObject, ValueNoSuchInstance, ValueEndOfMibView:
+		if len(content) != 0 {
+			return Value{}, 0, fmt.Errorf("ber: %v must be empty, got %d bytes", t, len(content))
+		}
+	default:
+		return Value{}, 0, fmt.Errorf("ber: unsupported tag 0x%02x", byte(t))
+	}
+	if err != nil {
+		return Value{}, 0, err
+	}
+	return v, start + length, nil
+}
+
+func encodeLength(length int) []byte {
+	if length < 0x80 {
+		return []byte{byte(length)}
+	}
+	var b []byte
+	for l := length; l > 0; l >>= 8 {
+		b = append([]byte{byte(l)}, b...)
+	}
+	return append([]byte{0x80 | byte(len(b))}, b...)
+}
+
+func decodeLength(b []byte) (int, int, error) {
+	if len(b) == 0 {
+		return 0, 0, fmt.Errorf("ber: truncated length")
+	}
+	if b[0] < 0x80 {
+		return int(b[0]), 1, nil
+	}
+	n := int(b[0] & 0x7f)
+	if n == 0 {
+		return 0, 0, fmt.Errorf("ber: indefinite length not supported")
+	}
+	if n > 4 {
+		return 0, 0, fmt.Errorf("ber: length of %d bytes too long", n)
+	}
+	if len(b) < 1+n {
+		return 0, 0, fmt.Errorf("ber: truncated length")
+	}
+	// The length is checked against the remaining bytes before it is
+	// converted, as four bytes may not fit in an int.
+	var length uint64
+	for _, c := range b[1 : 1+n] {
+		length = length<<8 | uint64(c)
+	}
+	if remaining := uint64(len(b) - 1 - n); length > remaining {
+		return 0, 0, fmt.Errorf("ber: length %d exceeds %d remaining bytes", length, remaining)
+	}
+	return int(length), 1 + n, nil
+}
+
+func encodeInt(i int64) []byte {
+	n := 1
+	for v := i; v > 127 || v < -128; v >>= 8 {
+		n++
+	}
+	b := make([]byte, n)
+	for j := n - 1; j >= 0; j-- {
+		b[j] = byte(i)
+		i >>= 8
+	}
+	return b
+}
+
+func decodeInt(b []byte) (int64, error) {
+	if len(b) == 0 {
+		return 0, fmt.Errorf("ber: empty integer")
+	}
+	if len(b) > 8 {
+		return 0, fmt.Errorf("ber: integer of %d bytes too large", len(b))
+	}
+	i := int64(int8(b[0]))
+	for _, c := range b[1:] {
+		i = i<<8 | int64(c)
+	}
+	return i, nil
+}
+
+func encodeUint(u uint64) []byte {
+	var b []byte
+	for v := u; v > 0; v >>= 8 {
+		b = append([]byte{byte(v)}, b...)
+	}
+	if len(b) == 0 || b[0]&0x80 != 0 {
+		b = append([]byte{0}, b...)
+	}
+	return b
+}
+
+// decodeUint decodes an unsigned value of the given size in bits. Some agents
+// omit the leading zero byte needed to keep large values positive, so the
+// content is treated as an unsigned magnitude.
+func decodeUint(b []byte, bits uint) (uint64, error) {
+	if len(b) == 0 {
+		return 0, fmt.Errorf("ber: empty integer")
+	}
+	if len(b) > 1 && b[0] == 0 {
+		b = b[1:]
+	}
+	if len(b) > int(bits/8) {
+		return 0, fmt.Errorf("ber: unsigned integer too large for %d bits", bits)
+	}
+	var u uint64
+	for _, c := range b {
+		u = u<<8 | uint64(c)
+	}
+	return u, nil
+}
+
+func encodeOID(oid OID) ([]byte, error) {
+	if err := oid.Validate(); err != nil {
+		return nil, fmt.Errorf("ber: %v", err)
+	}
+	if len(oid) < 2 {
+		return nil, fmt.Errorf("ber: OID %s must have at least two sub-identifiers", oid)
+	}
+	if oid[0] > 2 || (oid[0] < 2 && oid[1] >= 40) {
+		return nil, fmt.Errorf("ber: OID %s cannot be encoded", oid)
+	}
+	b := encodeSubID(nil, uint64(oid[0])*40+uint64(oid[1]))
+	for _, n := range oid[2:] {
+		b = encodeSubID(b, uint64(n))
+	}
+	return b, nil
+}
+
+func encodeSubID(b []byte, n uint64) []byte {
+	var tmp [10]byte
+	i := len(tmp) - 1
+	tmp[i] = byte(n & 0x7f)
+	for n >>= 7; n > 0; n >>= 7 {
+		i--
+		tmp[i] = 0x80 | byte(n&0x7f)
+	}
+	return append(b, tmp[i:]...)
+}
+
+func decodeOID(b []byte) (OID, error) {
+	if len(b) == 0 {
+		return nil, fmt.Errorf("ber: empty OID")
+	}
+	var oid OID
+	for i := 0; i < len(b); {
+		if b[i] == 0x80 {
+			return nil, fmt.Errorf("ber: OID sub-identifier has leading padding")
+		}
+		var n uint64
+		for {
+			if i == len(b) {
+				return nil, fmt.Errorf("ber: truncated OID sub-identifier")
+			}
+			c := b[i]
+			i++
+			n = n<<7 | uint64(c&0x7f)
+			if n > MaxSubID+80 {
+				return nil, fmt.Errorf("ber: OID sub-identifier too large")
+			}
+			if c&0x80 == 0 {
+				break
+			}
+		}
+		if oid == nil {
+			switch {
+			case n < 40:
+				oid = OID{0}
+			case n < 80:
+				oid = OID{1}
+				n -= 40
+			default:
+				oid = OID{2}
+				n -= 80
+			}
+		}
+		if n > min(MaxSubID, math.MaxInt) {
+			return nil, fmt.Errorf("ber: OID sub-identifier out of range: %d", n)
+		}
+		oid = append(oid, int(n))
+	}
+	if err := oid.Validate(); err != nil {
+		return nil, fmt.Errorf("ber: %v", err)
+	}
+	return oid, nil
+}
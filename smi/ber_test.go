@@ -0,0 +1,178 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/hallidave/mibtool/smi"
+)
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(strings.Replace(s, " ", "", -1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func valuesEqual(a, b smi.Value) bool {
+	return a.Type == b.Type && a.Int == b.Int && a.Uint == b.Uint &&
+		bytes.Equal(a.Bytes, b.Bytes) && a.OID.Equal(b.OID)
+}
+
+func TestBERRoundTrip(t *testing.T) {
+	tests := []struct {
+		value   smi.Value
+		encoded string
+	}{
+		{smi.Value{Type: smi.ValueInteger, Int: 0}, "02 01 00"},
+		{smi.Value{Type: smi.ValueInteger, Int: 127}, "02 01 7f"},
+		{smi.Value{Type: smi.ValueInteger, Int: 128}, "02 02 00 80"},
+		{smi.Value{Type: smi.ValueInteger, Int: -1}, "02 01 ff"},
+		{smi.Value{Type: smi.ValueInteger, Int: -128}, "02 01 80"},
+		{smi.Value{Type: smi.ValueInteger, Int: -129}, "02 02 ff 7f"},
+		{smi.Value{Type: smi.ValueInteger, Int: 2147483647}, "02 04 7f ff ff ff"},
+		{smi.Value{Type: smi.ValueInteger, Int: -2147483648}, "02 04 80 00 00 00"},
+		{smi.Value{Type: smi.ValueCounter32, Uint: 0}, "41 01 00"},
+		{smi.Value{Type: smi.ValueCounter32, Uint: 4294967295}, "41 05 00 ff ff ff ff"},
+		{smi.Value{Type: smi.ValueGauge32, Uint: 1000000000}, "42 04 3b 9a ca 00"},
+		{smi.Value{Type: smi.ValueTimeTicks, Uint: 128}, "43 02 00 80"},
+		{smi.Value{Type: smi.ValueCounter64, Uint: 18446744073709551615}, "46 09 00 ff ff ff ff ff ff ff ff"},
+		{smi.Value{Type: smi.ValueOctetString, Bytes: []byte("eth0")}, "04 04 65 74 68 30"},
+		{smi.Value{Type: smi.ValueOctetString, Bytes: []byte{}}, "04 00"},
+		{smi.Value{Type: smi.ValueOpaque, Bytes: []byte{0x9f, 0x78, 0x04, 0x42, 0xf6, 0x00, 0x00}}, "44 07 9f 78 04 42 f6 00 00"},
+		{smi.Value{Type: smi.ValueIPAddress, Bytes: []byte{192, 168, 1, 1}}, "40 04 c0 a8 01 01"},
+		{smi.Value{Type: smi.ValueObjectID, OID: smi.OID{1, 3, 6, 1, 2, 1, 1, 1, 0}}, "06 08 2b 06 01 02 01 01 01 00"},
+		{smi.Value{Type: smi.ValueObjectID, OID: smi.OID{0, 0}}, "06 01 00"},
+		{smi.Value{Type: smi.ValueObjectID, OID: smi.OID{2, 999, 3}}, "06 03 88 37 03"},
+		{smi.Value{Type: smi.ValueObjectID, OID: smi.OID{1, 3, 6, 1, 4, 1, 2147483647}}, "06 0a 2b 06 01 04 01 87 ff ff ff 7f"},
+		{smi.Value{Type: smi.ValueNull}, "05 00"},
+		{smi.Value{Type: smi.ValueNoSuchObject}, "80 00"},
+		{smi.Value{Type: smi.ValueNoSuchInstance}, "81 00"},
+		{smi.Value{Type: smi.ValueEndOfMibView}, "82 00"},
+	}
+	for _, test := range tests {
+		expected := mustDecodeHex(t, test.encoded)
+		encoded, err := smi.EncodeValue(test.value)
+		if err != nil {
+			t.Errorf("%v %v: %v", test.value.Type, test.value, err)
+			continue
+		}
+		if !bytes.Equal(encoded, expected) {
+			t.Errorf("%v %v: got % x, expected % x", test.value.Type, test.value, encoded, expected)
+		}
+		decoded, n, err := smi.DecodeValue(append(expected, 0xff))
+		if err != nil {
+			t.Errorf("% x: %v", expected, err)
+			continue
+		}
+		if n != len(expected) {
+			t.Errorf("% x: read %d bytes, expected %d", expected, n, len(expected))
+		}
+		if !valuesEqual(decoded, test.value) {
+			t.Errorf("% x: got %v %v, expected %v %v", expected, decoded.Type, decoded, test.value.Type, test.value)
+		}
+	}
+}
+
+func TestBERLongLength(t *testing.T) {
+	for _, size := range []int{127, 128, 255, 256, 70000} {
+		value := smi.Value{Type: smi.ValueOctetString, Bytes: bytes.Repeat([]byte{'x'}, size)}
+		encoded, err := smi.EncodeValue(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, n, err := smi.DecodeValue(encoded)
+		if err != nil {
+			t.Fatalf("%d: %v", size, err)
+		}
+		if n != len(encoded) || !valuesEqual(decoded, value) {
+			t.Errorf("%d: round trip failed", size)
+		}
+	}
+	encoded, _ := smi.EncodeValue(smi.Value{Type: smi.ValueOctetString, Bytes: make([]byte, 200)})
+	if !bytes.Equal(encoded[:3], []byte{0x04, 0x81, 0xc8}) {
+		t.Errorf("got % x", encoded[:3])
+	}
+}
+
+func TestBERDecodeLenient(t *testing.T) {
+	// Counter32 without the leading zero byte
+	v, _, err := smi.DecodeValue(mustDecodeHex(t, "41 04 ff ff ff ff"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Uint != 4294967295 {
+		t.Errorf("got %d", v.Uint)
+	}
+}
+
+func TestBERDecodeErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"02",
+		"02 01",
+		"02 00",
+		"02 09 01 02 03 04 05 06 07 08 09",
+		"04 80 00 00",
+		"04 85 01 02 03 04 05",
+		"04 82 01",
+		"04 84 ff ff ff ff 00",
+		"04 84 80 00 00 00",
+		"41 06 01 00 00 00 00 00",
+		"40 03 01 02 03",
+		"06 00",
+		"06 02 2b 86",
+		"06 03 2b 80 01",
+		"06 06 2b 90 80 80 80 00",
+		"05 01 00",
+		"30 00",
+	}
+	for _, test := range tests {
+		if v, _, err := smi.DecodeValue(mustDecodeHex(t, test)); err == nil {
+			t.Errorf("%s: expected error, got %v %v", test, v.Type, v)
+		}
+	}
+}
+
+func TestBEREncodeErrors(t *testing.T) {
+	tests := []smi.Value{
+		{Type: smi.ValueObjectID, OID: smi.OID{1}},
+		{Type: smi.ValueObjectID, OID: smi.OID{3, 1}},
+		{Type: smi.ValueObjectID, OID: smi.OID{1, 40}},
+		{Type: smi.ValueObjectID, OID: smi.OID{1, 3, -1}},
+		{Type: smi.ValueIPAddress, Bytes: []byte{1, 2, 3}},
+		{Type: smi.ValueGauge32, Uint: 1 << 32},
+		{Type: smi.ValueType(0x30)},
+	}
+	for _, test := range tests {
+		if _, err := smi.EncodeValue(test); err == nil {
+			t.Errorf("%v %v: expected error", test.Type, test)
+		}
+	}
+}
+
+func TestDecodeOID(t *testing.T) {
+	oid := smi.OID{1, 3, 6, 1, 2, 1, 2, 2, 1, 2, 3}
+	encoded, err := oid.EncodeBER()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, n, err := smi.DecodeOID(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(encoded) || !decoded.Equal(oid) {
+		t.Errorf("got %s", decoded)
+	}
+	if _, _, err := smi.DecodeOID(mustDecodeHex(t, "02 01 00")); err == nil {
+		t.Error("expected error decoding INTEGER as OID")
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi
+
+import (
+	"fmt"
+	"log"
+)
+
+// DiagnosticKind identifies the event reported by a Diagnostic.
+type DiagnosticKind int
+
+// DiagnosticKind values for the events reported while loading modules
+const (
+	// DiagDuplicateSymbol reports that Symbol, defined by Module at Line,
+	// has the same name as Related, a symbol defined by an earlier module.
+	DiagDuplicateSymbol DiagnosticKind = iota + 1
+
+	// DiagMissingImport reports that Module imports from the module named
+	// by Related at Line, but that module is not in the MIB.
+	DiagMissingImport
+
+	// DiagModuleAlias reports that the alias named by Related was used for Module.
+	DiagModuleAlias
+
+	// DiagFileReplaced reports that Module is now found in File instead of
+	// the file named by Related.
+	DiagFileReplaced
+
+	// DiagFileModified reports that the File of a loaded Module has changed.
+	DiagFileModified
+
+	// DiagFileRemoved reports that the File of a loaded Module has been removed.
+	DiagFileRemoved
+
+	// DiagNotAModule reports that File in a MIB directory was skipped because
+	// it does not contain a module.
+	DiagNotAModule
+)
+
+var diagnosticKindNames = map[DiagnosticKind]string{
+	DiagDuplicateSymbol: "duplicate symbol",
+	DiagMissingImport:   "missing import",
+	DiagModuleAlias:     "module alias",
+	DiagFileReplaced:    "file replaced",
+	DiagFileModified:    "file modified",
+	DiagFileRemoved:     "file removed",
+	DiagNotAModule:      "not a module",
+}
+
+func (k DiagnosticKind) String() string {
+	if name, ok := diagnosticKindNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("DiagnosticKind(%d)", int(k))
+}
+
+// A Diagnostic describes an event that occurred while loading modules that
+// did not prevent the modules from loading. The meaning of the Symbol and
+// Related fields depends on the Kind. Fields that do not apply are empty and
+// Line is 0 if the position is not known.
+type Diagnostic struct {
+	Kind    DiagnosticKind
+	Module  string
+	File    string
+	Line    int
+	Symbol  string
+	Related string
+}
+
+func (d Diagnostic) String() string {
+	var msg string
+	switch d.Kind {
+	case DiagDuplicateSymbol:
+		msg = fmt.Sprintf("symbol %s::%s duplicates name of %s", d.Module, d.Symbol, d.Related)
+	case DiagMissingImport:
+		msg = fmt.Sprintf("module %s: imported module not found: %s", d.Module, d.Related)
+	case DiagModuleAlias:
+		msg = fmt.Sprintf("module alias %s: using %s", d.Related, d.Module)
+	case DiagFileReplaced:
+		msg = fmt.Sprintf("module %s: replacing %s with %s", d.Module, d.Related, d.File)
+	case DiagFileModified:
+		msg = fmt.Sprintf("module %s: %s modified", d.Module, d.File)
+	case DiagFileRemoved:
+		msg = fmt.Sprintf("module %s: %s removed", d.Module, d.File)
+	case DiagNotAModule:
+		msg = fmt.Sprintf("not a module file: %s", d.File)
+	default:
+		msg = d.Kind.String()
+	}
+	if d.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", d.File, d.Line, msg)
+	}
+	return msg
+}
+
+// report passes a diagnostic to the Diagnostics function of the MIB. If no
+// function is set and the Debug flag is set, the diagnostic is logged instead.
+func (mib *MIB) report(d Diagnostic) {
+	if mib.Diagnostics != nil {
+		mib.Diagnostics(d)
+	} else if mib.Debug {
+		log.Print(d)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hallidave/mibtool/smi"
+)
+
+func TestDiagnostics(t *testing.T) {
+	var diags []smi.Diagnostic
+	mib := smi.NewMIB("testdata")
+	mib.Diagnostics = func(d smi.Diagnostic) {
+		diags = append(diags, d)
+	}
+	err := mib.LoadModules("RMON2-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var found bool
+	for _, d := range diags {
+		if d.Kind == smi.DiagDuplicateSymbol && d.Symbol == "rmon" {
+			found = true
+			if d.Module != "RMON2-MIB" || d.Related != "RMON-MIB::rmon" || d.Line != 18 {
+				t.Errorf("unexpected diagnostic: %+v", d)
+			}
+			if filepath.Base(d.File) != "RMON2-MIB" {
+				t.Errorf("unexpected file: %s", d.File)
+			}
+			expected := d.File + ":18: symbol RMON2-MIB::rmon duplicates name of RMON-MIB::rmon"
+			if d.String() != expected {
+				t.Errorf("got %q, expected %q", d.String(), expected)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("expected duplicate symbol diagnostic, got %v", diags)
+	}
+}
+
+func TestDiagnosticsFiles(t *testing.T) {
+	dir := setupReloadDir(t)
+	defer os.RemoveAll(dir)
+	writeMIBFile(t, dir, "README", []byte("not a MIB\n"))
+
+	diags := make(map[smi.DiagnosticKind][]smi.Diagnostic)
+	mib := smi.NewMIB(dir)
+	mib.Diagnostics = func(d smi.Diagnostic) {
+		diags[d.Kind] = append(diags[d.Kind], d)
+	}
+	err := mib.LoadModules("RELOAD-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := diags[smi.DiagNotAModule]; len(d) != 1 || filepath.Base(d[0].File) != "README" {
+		t.Errorf("not a module: got %v", d)
+	}
+
+	writeMIBFile(t, dir, "RELOAD-MIB", []byte(reloadMIBv2))
+	_, err = mib.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := diags[smi.DiagFileModified]; len(d) != 1 || d[0].Module != "RELOAD-MIB" {
+		t.Errorf("file modified: got %v", d)
+	}
+}
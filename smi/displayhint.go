@@ -0,0 +1,419 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// octetFormat is one octet-format specification of an OCTET STRING display
+// hint, as described in RFC 2579 section 3.1. Separator and terminator are 0
+// if the specification has none.
+type octetFormat struct {
+	repeat     bool
+	length     int
+	format     byte
+	separator  byte
+	terminator byte
+}
+
+// parseOctetHint parses the display hint of an OCTET STRING textual convention.
+func parseOctetHint(hint string) ([]octetFormat, error) {
+	var specs []octetFormat
+	for i := 0; i < len(hint); {
+		var f octetFormat
+		if hint[i] == '*' {
+			f.repeat = true
+			i++
+		}
+		start := i
+		for i < len(hint) && isDigitByte(hint[i]) {
+			i++
+		}
+		if i == start {
+			return nil, fmt.Errorf("display hint %q: expected octet length at offset %d", hint, i)
+		}
+		n, err := strconv.Atoi(hint[start:i])
+		if err != nil {
+			return nil, fmt.Errorf("display hint %q: invalid octet length %s", hint, hint[start:i])
+		}
+		f.length = n
+		if i == len(hint) {
+			return nil, fmt.Errorf("display hint %q: expected format after octet length", hint)
+		}
+		switch hint[i] {
+		case 'd', 'x', 'o', 'a', 't':
+			f.format = hint[i]
+		default:
+			return nil, fmt.Errorf("display hint %q: unknown format %q", hint, hint[i])
+		}
+		i++
+		if i < len(hint) && isHintSeparator(hint[i]) {
+			f.separator = hint[i]
+			i++
+			if f.repeat && i < len(hint) && isHintSeparator(hint[i]) {
+				f.terminator = hint[i]
+				i++
+			}
+		}
+		specs = append(specs, f)
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("empty display hint")
+	}
+	return specs, nil
+}
+
+// isHintSeparator returns true if c can be a separator or terminator, which
+// is any character that cannot start the next octet-format specification.
+func isHintSeparator(c byte) bool {
+	return !isDigitByte(c) && c != '*'
+}
+
+// FormatOctets formats an OCTET STRING value according to an RFC 2579
+// DISPLAY-HINT such as "1x:", "255a" or "2d-1d-1d,1d:1d:1d.1d,1a1d:1d".
+// The specifications of the hint are applied in turn, with the last one
+// applied repeatedly until the value is used up. Hexadecimal numbers are
+// written in lower case with two digits for each octet. No separator or
+// terminator is written after the last octet.
+func FormatOctets(hint string, value []byte) (string, error) {
+	specs, err := parseOctetHint(hint)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for i := 0; len(value) > 0; i++ {
+		f := specs[min(i, len(specs)-1)]
+		remaining := len(value)
+		count := 1
+		if f.repeat {
+			count = int(value[0])
+			value = value[1:]
+		}
+		for r := 0; r < count && len(value) > 0; r++ {
+			n := min(f.length, len(value))
+			f.write(&b, value[:n])
+			value = value[n:]
+			if len(value) > 0 && f.separator != 0 && (r < count-1 || f.terminator == 0) {
+				b.WriteByte(f.separator)
+			}
+		}
+		if len(value) > 0 && f.terminator != 0 {
+			b.WriteByte(f.terminator)
+		}
+		if len(value) == remaining && i >= len(specs)-1 {
+			return "", fmt.Errorf("display hint %q does not consume any octets", hint)
+		}
+	}
+	return b.String(), nil
+}
+
+// write formats the octets taken by one application of the specification.
+func (f octetFormat) write(b *strings.Builder, octets []byte) {
+	switch f.format {
+	case 'a', 't':
+		b.Write(octets)
+	case 'x':
+		b.WriteString(hex.EncodeToString(octets))
+	case 'd':
+		b.WriteString(new(big.Int).SetBytes(octets).Text(10))
+	case 'o':
+		b.WriteString(new(big.Int).SetBytes(octets).Text(8))
+	}
+}
+
+// FormatInteger formats an INTEGER value according to an RFC 2579
+// DISPLAY-HINT: "d" for decimal, "d-N" for decimal with a decimal point N
+// digits from the right, "x" for hexadecimal, "o" for octal and "b" for
+// binary. Negative values are written with a leading minus sign.
+func FormatInteger(hint string, value int64) (string, error) {
+	abs := uint64(value)
+	sign := ""
+	if value < 0 {
+		abs = uint64(-value)
+		sign = "-"
+	}
+	switch {
+	case hint == "x":
+		return sign + strconv.FormatUint(abs, 16), nil
+	case hint == "o":
+		return sign + strconv.FormatUint(abs, 8), nil
+	case hint == "b":
+		return sign + strconv.FormatUint(abs, 2), nil
+	case hint == "d":
+		return strconv.FormatInt(value, 10), nil
+	case strings.HasPrefix(hint, "d-"):
+		places, err := strconv.Atoi(hint[2:])
+		if err != nil || places < 0 || !isNumber(hint[2:]) {
+			return "", fmt.Errorf("display hint %q: invalid number of decimal places", hint)
+		}
+		digits := strconv.FormatUint(abs, 10)
+		if len(digits) <= places {
+			digits = strings.Repeat("0", places-len(digits)+1) + digits
+		}
+		point := len(digits) - places
+		if places == 0 {
+			return sign + digits, nil
+		}
+		return sign + digits[:point] + "." + digits[point:], nil
+	}
+	return "", fmt.Errorf("display hint %q: unknown integer format", hint)
+}
+
+// DisplayHint returns the DISPLAY-HINT of the textual convention used by the
+// object sym, or the empty string if the type of the object has none. When
+// textual conventions are derived from each other the hint of the most
+// refined one is returned.
+func (mib *MIB) DisplayHint(sym *Symbol) string {
+	if sym.Node == nil || sym.Node.Syntax == nil {
+		return ""
+	}
+	mod := sym.Module
+	name := sym.Node.Syntax.Type
+	for depth := 0; depth < MaxTypeDepth && !smiBaseTypes[name]; depth++ {
+		var t *Type
+		mod, t = mib.findType(mod, name)
+		if t == nil {
+			return ""
+		}
+		if t.DisplayHint != "" {
+			return t.DisplayHint
+		}
+		name = t.Syntax.Type
+	}
+	return ""
+}
+
+// FormatValue formats a value of the object sym using the DISPLAY-HINT of its
+// textual convention. The value of an OCTET STRING object is given as a
+// []byte or string and the value of an integer object as any integer type.
+// Without a display hint, integers are written in decimal and octet strings
+// as text if they are printable, or otherwise as hexadecimal octets
+// separated by spaces.
+func (mib *MIB) FormatValue(sym *Symbol, value interface{}) (string, error) {
+	if sym.Node == nil || sym.Node.Syntax == nil {
+		return "", fmt.Errorf("%s is not an object with a syntax", sym)
+	}
+	hint := mib.DisplayHint(sym)
+	switch v := value.(type) {
+	case string:
+		return formatOctetsValue(hint, []byte(v))
+	case []byte:
+		return formatOctetsValue(hint, v)
+	case uint64:
+		if hint == "" || v > 1<<63-1 {
+			return strconv.FormatUint(v, 10), nil
+		}
+		return FormatInteger(hint, int64(v))
+	}
+	n, ok := integerValue(value)
+	if !ok {
+		return "", fmt.Errorf("%s: unsupported value type %T", sym, value)
+	}
+	if hint == "" {
+		return strconv.FormatInt(n, 10), nil
+	}
+	return FormatInteger(hint, n)
+}
+
+func formatOctetsValue(hint string, value []byte) (string, error) {
+	if hint != "" {
+		return FormatOctets(hint, value)
+	}
+	if isPrintable(value) {
+		return string(value), nil
+	}
+	return FormatOctets("1x ", value)
+}
+
+// integerValue converts a value of any integer type except uint64 to int64.
+func integerValue(value interface{}) (int64, bool) {
+	switch v := plainValue(value).(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return int64(v), true
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+// ParseOctets is the inverse of FormatOctets. It converts text written in the
+// format of an OCTET STRING display hint, such as "00:1a:2b:3c:4d:5e" for
+// "1x:", to the octets of the value. Numbers that do not fit in the octet
+// length of their specification and text that does not match the separators
+// of the hint are errors.
+func ParseOctets(hint string, text string) ([]byte, error) {
+	specs, err := parseOctetHint(hint)
+	if err != nil {
+		return nil, err
+	}
+	var value []byte
+	s := text
+	for i := 0; len(s) > 0; i++ {
+		f := specs[min(i, len(specs)-1)]
+		remaining := len(s)
+		if !f.repeat {
+			value, s, err = f.parse(value, s)
+			if err == nil {
+				s, err = f.skipSeparator(s)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("display hint %q: %q: %v", hint, text, err)
+			}
+		} else {
+			countOffset := len(value)
+			value = append(value, 0)
+			count := 0
+			for len(s) > 0 {
+				value, s, err = f.parse(value, s)
+				if err != nil {
+					return nil, fmt.Errorf("display hint %q: %q: %v", hint, text, err)
+				}
+				count++
+				if count > 255 {
+					return nil, fmt.Errorf("display hint %q: %q: more than 255 repetitions", hint, text)
+				}
+				if f.terminator != 0 && len(s) > 0 && s[0] == f.terminator {
+					s = s[1:]
+					break
+				}
+				if s, err = f.skipSeparator(s); err != nil {
+					return nil, fmt.Errorf("display hint %q: %q: %v", hint, text, err)
+				}
+			}
+			value[countOffset] = byte(count)
+		}
+		if len(s) == remaining {
+			return nil, fmt.Errorf("display hint %q: %q: unexpected %q", hint, text, s[0])
+		}
+	}
+	return value, nil
+}
+
+// parse converts the text of one application of the specification at the
+// start of s, appending the octets to value. It returns the rest of s.
+func (f octetFormat) parse(value []byte, s string) ([]byte, string, error) {
+	if f.format == 'a' || f.format == 't' {
+		n := 0
+		for n < len(s) && n < f.length && s[n] != f.separator && (f.terminator == 0 || s[n] != f.terminator) {
+			n++
+		}
+		return append(value, s[:n]...), s[n:], nil
+	}
+
+	base := 10
+	maxDigits := len(s)
+	switch f.format {
+	case 'x':
+		base = 16
+		maxDigits = 2 * f.length
+	case 'o':
+		base = 8
+	}
+	n := 0
+	for n < len(s) && n < maxDigits && isBaseDigit(s[n], base) {
+		n++
+	}
+	if n == 0 {
+		return nil, "", fmt.Errorf("expected a number at %q", s)
+	}
+	number, _ := new(big.Int).SetString(s[:n], base)
+	if (number.BitLen()+7)/8 > f.length {
+		return nil, "", fmt.Errorf("%s does not fit in %d octets", s[:n], f.length)
+	}
+	return append(value, number.FillBytes(make([]byte, f.length))...), s[n:], nil
+}
+
+// skipSeparator removes the separator of the specification from the start of
+// s. The separator is optional at the end of the text.
+func (f octetFormat) skipSeparator(s string) (string, error) {
+	if f.separator == 0 || len(s) == 0 {
+		return s, nil
+	}
+	if s[0] != f.separator {
+		return "", fmt.Errorf("expected %q at %q", f.separator, s)
+	}
+	return s[1:], nil
+}
+
+// isDigits returns true if s is a non-empty string of decimal digits.
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isDigitByte(s[i]) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isBaseDigit(c byte, base int) bool {
+	switch base {
+	case 2:
+		return c == '0' || c == '1'
+	case 8:
+		return c >= '0' && c <= '7'
+	case 16:
+		return isHexDigitByte(c)
+	}
+	return isDigitByte(c)
+}
+
+// ParseInteger is the inverse of FormatInteger. It converts text written in
+// the format of an INTEGER display hint to a value. For a "d-N" hint the
+// text may have up to N digits after the decimal point.
+func ParseInteger(hint string, text string) (int64, error) {
+	base := 0
+	switch {
+	case hint == "x":
+		base = 16
+	case hint == "o":
+		base = 8
+	case hint == "b":
+		base = 2
+	case hint == "d":
+		base = 10
+	case strings.HasPrefix(hint, "d-"):
+		places, err := strconv.Atoi(hint[2:])
+		if err != nil || places < 0 || !isNumber(hint[2:]) {
+			return 0, fmt.Errorf("display hint %q: invalid number of decimal places", hint)
+		}
+		whole, fraction, _ := strings.Cut(text, ".")
+		if len(fraction) > places || !isDigits(strings.TrimPrefix(whole, "-")+fraction) {
+			return 0, fmt.Errorf("display hint %q: invalid number %q", hint, text)
+		}
+		text = whole + fraction + strings.Repeat("0", places-len(fraction))
+		base = 10
+	default:
+		return 0, fmt.Errorf("display hint %q: unknown integer format", hint)
+	}
+	abs := strings.TrimPrefix(text, "-")
+	if abs == "" || strings.HasPrefix(abs, "+") || strings.HasPrefix(abs, "-") {
+		return 0, fmt.Errorf("display hint %q: invalid number %q", hint, text)
+	}
+	n, err := strconv.ParseInt(text, base, 64)
+	if err != nil {
+		return 0, fmt.Errorf("display hint %q: invalid number %q", hint, text)
+	}
+	return n, nil
+}
@@ -0,0 +1,230 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi_test
+
+import (
+	"testing"
+
+	"github.com/hallidave/mibtool/smi"
+)
+
+func TestFormatOctets(t *testing.T) {
+	dateAndTime := []byte{0x07, 0xe8, 3, 1, 10, 0, 0, 0, '+', 0, 0}
+	tests := []struct {
+		hint     string
+		value    []byte
+		expected string
+	}{
+		{"1x:", []byte{0x00, 0x1a, 0x2b, 0x3c, 0x4d, 0x5e}, "00:1a:2b:3c:4d:5e"},
+		{"255a", []byte("eth0"), "eth0"},
+		{"255t", []byte("caf\xc3\xa9"), "caf\xc3\xa9"},
+		{"2d-1d-1d,1d:1d:1d.1d,1a1d:1d", dateAndTime, "2024-3-1,10:0:0.0,+0:0"},
+		{"2d-1d-1d,1d:1d:1d.1d,1a1d:1d", dateAndTime[:8], "2024-3-1,10:0:0.0"},
+		{"1d.1d.1d.1d%4d", []byte{192, 168, 0, 1, 0, 0, 0, 3}, "192.168.0.1%3"},
+		{"2x:2x:2x:2x:2x:2x:2x:2x", []byte{0xfe, 0x80, 15: 1}, "fe80:0000:0000:0000:0000:0000:0000:0001"},
+		{"1o", []byte{8, 9}, "1011"},
+		{"4d", []byte{1, 0, 0, 0, 0, 2}, "16777216" + "2"},
+		{"*1x:/1a", []byte{2, 0xaa, 0xbb, 'x', 'y'}, "aa:bb/xy"},
+		{"*1d./", []byte{2, 1, 2, 3, 4, 5}, "1.2/4.5"},
+		{"1d.", []byte{}, ""},
+	}
+	for _, test := range tests {
+		s, err := smi.FormatOctets(test.hint, test.value)
+		if err != nil {
+			t.Errorf("%s: %v", test.hint, err)
+			continue
+		}
+		if s != test.expected {
+			t.Errorf("%s: got %q, expected %q", test.hint, s, test.expected)
+		}
+	}
+
+	for _, hint := range []string{"", "x", "1", "1q", "*a", "0a"} {
+		if _, err := smi.FormatOctets(hint, []byte{1}); err == nil {
+			t.Errorf("%q: expected error", hint)
+		}
+	}
+}
+
+func TestFormatInteger(t *testing.T) {
+	tests := []struct {
+		hint     string
+		value    int64
+		expected string
+	}{
+		{"d", 1234, "1234"},
+		{"d", -1234, "-1234"},
+		{"d-2", 1234, "12.34"},
+		{"d-2", 5, "0.05"},
+		{"d-2", -5, "-0.05"},
+		{"d-3", -1234, "-1.234"},
+		{"d-0", 42, "42"},
+		{"x", 255, "ff"},
+		{"x", -255, "-ff"},
+		{"o", 8, "10"},
+		{"b", 5, "101"},
+	}
+	for _, test := range tests {
+		s, err := smi.FormatInteger(test.hint, test.value)
+		if err != nil {
+			t.Errorf("%s: %v", test.hint, err)
+			continue
+		}
+		if s != test.expected {
+			t.Errorf("%s %d: got %q, expected %q", test.hint, test.value, s, test.expected)
+		}
+	}
+
+	for _, hint := range []string{"", "1x", "d-", "d-x", "d--1", "z"} {
+		if _, err := smi.FormatInteger(hint, 1); err == nil {
+			t.Errorf("%q: expected error", hint)
+		}
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	mib := smi.NewMIB("testdata")
+	err := mib.LoadModules("IF-MIB", "HOST-RESOURCES-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		value    interface{}
+		hint     string
+		expected string
+	}{
+		{"ifPhysAddress", []byte{0, 0x1a, 0x2b, 0x3c, 0x4d, 0x5e}, "1x:", "00:1a:2b:3c:4d:5e"},
+		{"ifDescr", "eth0", "255a", "eth0"},
+		{"ifIndex", int32(3), "d", "3"},
+		{"ifMtu", 1500, "", "1500"},
+		{"ifAlias", "uplink", "255a", "uplink"},
+		{"hrSystemDate", []byte{0x07, 0xe8, 3, 1, 10, 0, 0, 0}, "2d-1d-1d,1d:1d:1d.1d,1a1d:1d", "2024-3-1,10:0:0.0"},
+		{"hrSWRunName", "sshd", "", "sshd"},
+		{"hrSWRunName", []byte{0xff, 0x00}, "", "ff 00"},
+	}
+	for _, test := range tests {
+		sym := mib.Symbols[test.name]
+		if sym == nil {
+			t.Errorf("%s: symbol not found", test.name)
+			continue
+		}
+		if hint := mib.DisplayHint(sym); hint != test.hint {
+			t.Errorf("%s: got hint %q, expected %q", test.name, hint, test.hint)
+		}
+		s, err := mib.FormatValue(sym, test.value)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if s != test.expected {
+			t.Errorf("%s: got %q, expected %q", test.name, s, test.expected)
+		}
+	}
+
+	if _, err := mib.FormatValue(mib.Symbols["ifIndex"], 1.5); err == nil {
+		t.Error("expected error for float value")
+	}
+}
+
+func TestParseOctets(t *testing.T) {
+	tests := []struct {
+		hint     string
+		text     string
+		expected []byte
+	}{
+		{"1x:", "00:1a:2b:3c:4d:5e", []byte{0x00, 0x1a, 0x2b, 0x3c, 0x4d, 0x5e}},
+		{"1x:", "0:1A:2b", []byte{0x00, 0x1a, 0x2b}},
+		{"255a", "eth0", []byte("eth0")},
+		{"2d-1d-1d,1d:1d:1d.1d,1a1d:1d", "2024-3-1,10:00:00.0,+0:0", []byte{0x07, 0xe8, 3, 1, 10, 0, 0, 0, '+', 0, 0}},
+		{"2d-1d-1d,1d:1d:1d.1d,1a1d:1d", "2024-3-1,10:00:00.0", []byte{0x07, 0xe8, 3, 1, 10, 0, 0, 0}},
+		{"1d.1d.1d.1d%4d", "192.168.0.1%3", []byte{192, 168, 0, 1, 0, 0, 0, 3}},
+		{"2x:2x", "fe80:1", []byte{0xfe, 0x80, 0x00, 0x01}},
+		{"*1x:/1a", "aa:bb/xy", []byte{2, 0xaa, 0xbb, 'x', 'y'}},
+		{"1o ", "10 11", []byte{8, 9}},
+		{"1d.", "", nil},
+	}
+	for _, test := range tests {
+		value, err := smi.ParseOctets(test.hint, test.text)
+		if err != nil {
+			t.Errorf("%s %q: %v", test.hint, test.text, err)
+			continue
+		}
+		if string(value) != string(test.expected) {
+			t.Errorf("%s %q: got %v, expected %v", test.hint, test.text, value, test.expected)
+		}
+		if text, err := smi.FormatOctets(test.hint, value); err != nil || len(test.text) > 0 && text == "" {
+			t.Errorf("%s %q: cannot format result: %q, %v", test.hint, test.text, text, err)
+		}
+	}
+
+	errors := []struct {
+		hint string
+		text string
+	}{
+		{"1x:", "00-1a"},
+		{"1x:", "100:1a"},
+		{"1d.", "256.1"},
+		{"1d.", "a.b"},
+		{"2d-1d", "2024/3"},
+	}
+	for _, test := range errors {
+		if value, err := smi.ParseOctets(test.hint, test.text); err == nil {
+			t.Errorf("%s %q: got %v, expected error", test.hint, test.text, value)
+		}
+	}
+}
+
+func TestParseInteger(t *testing.T) {
+	tests := []struct {
+		hint     string
+		text     string
+		expected int64
+	}{
+		{"d", "1234", 1234},
+		{"d", "-1234", -1234},
+		{"d-2", "12.34", 1234},
+		{"d-2", "12.3", 1230},
+		{"d-2", "12", 1200},
+		{"d-2", "0.05", 5},
+		{"d-2", "-0.05", -5},
+		{"x", "ff", 255},
+		{"x", "-FF", -255},
+		{"o", "10", 8},
+		{"b", "101", 5},
+	}
+	for _, test := range tests {
+		n, err := smi.ParseInteger(test.hint, test.text)
+		if err != nil {
+			t.Errorf("%s %q: %v", test.hint, test.text, err)
+			continue
+		}
+		if n != test.expected {
+			t.Errorf("%s %q: got %d, expected %d", test.hint, test.text, n, test.expected)
+		}
+	}
+
+	errors := []struct {
+		hint string
+		text string
+	}{
+		{"d", ""},
+		{"d", "+1"},
+		{"d", "1.5"},
+		{"d-2", "1.234"},
+		{"d-2", "1.-2"},
+		{"d-2", "."},
+		{"x", "0x10"},
+		{"b", "102"},
+		{"q", "1"},
+	}
+	for _, test := range errors {
+		if n, err := smi.ParseInteger(test.hint, test.text); err == nil {
+			t.Errorf("%s %q: got %d, expected error", test.hint, test.text, n)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi
+
+// A DuplicatePolicy determines which definition of a name is found by name
+// lookups when several loaded modules define the same name.
+type DuplicatePolicy int
+
+// DuplicatePolicy values
+const (
+	// PreferFirstLoaded uses the definition from the module that was loaded first.
+	// Imported modules are loaded before the modules that import from them.
+	PreferFirstLoaded DuplicatePolicy = iota
+
+	// PreferModules uses the definition from the module that appears first in
+	// the list of preferred modules, falling back to PreferFirstLoaded if none
+	// of the defining modules are listed.
+	PreferModules
+
+	// RejectAmbiguous refuses to resolve a name that is defined with different
+	// OIDs by several modules. Such names must be qualified with a module name.
+	RejectAmbiguous
+)
+
+// SetDuplicatePolicy sets the policy for resolving names that are defined by
+// more than one module. The modules argument is the list of preferred modules
+// used by the PreferModules policy. The policy applies to the Symbols map and
+// to the OID function, and takes effect immediately. The default policy is
+// PreferFirstLoaded.
+func (mib *MIB) SetDuplicatePolicy(policy DuplicatePolicy, modules ...string) {
+	mib.duplicatePolicy = policy
+	mib.preferredModules = modules
+	mib.resolveDuplicates()
+}
+
+// SymbolDefinitions returns all of the symbols with the given name in the
+// order their modules were loaded.
+func (mib *MIB) SymbolDefinitions(name string) []*Symbol {
+	return append([]*Symbol(nil), mib.definitions[name]...)
+}
+
+// DefiningModules returns the names of the modules that define the given
+// name in the order they were loaded.
+func (mib *MIB) DefiningModules(name string) []string {
+	var modNames []string
+	for _, sym := range mib.definitions[name] {
+		if sym.Module != nil {
+			modNames = append(modNames, sym.Module.Name)
+		}
+	}
+	return modNames
+}
+
+// resolveDuplicates applies the duplicate policy to every name with more than
+// one definition. Where the definitions share an OID, the chosen symbol is also
+// the one found in the tree, by ID and by label.
+func (mib *MIB) resolveDuplicates() {
+	for name, defs := range mib.definitions {
+		if len(defs) < 2 {
+			continue
+		}
+		sym := mib.chooseDefinition(defs)
+		if sym == nil {
+			delete(mib.Symbols, name)
+			continue
+		}
+		mib.Symbols[name] = sym
+		if sym.Parent == nil {
+			continue
+		}
+		if node := sym.Parent.ChildByID[sym.ID]; node != nil && node != sym && node.Name == sym.Name {
+			// The replacement takes over the children of the node in the
+			// tree, which may have been sorted before the policy changed.
+			sym.ChildByID = node.ChildByID
+			sym.ChildByLabel = node.ChildByLabel
+			sym.childIDs = node.childIDs
+			sym.Parent.ChildByID[sym.ID] = sym
+		}
+		// attachSymbol leaves the label naming the last definition loaded,
+		// which need not be the one chosen.
+		if node := sym.Parent.ChildByLabel[sym.Name]; node != nil && node != sym {
+			sym.Parent.ChildByLabel[sym.Name] = sym
+		}
+	}
+}
+
+func (mib *MIB) chooseDefinition(defs []*Symbol) *Symbol {
+	switch mib.duplicatePolicy {
+	case PreferModules:
+		for _, modName := range mib.preferredModules {
+			modName = mib.moduleName(modName)
+			for _, sym := range defs {
+				if sym.Module != nil && sym.Module.Name == modName {
+					return sym
+				}
+			}
+		}
+	case RejectAmbiguous:
+		oid := mib.symbolOID(defs[0])
+		for _, sym := range defs[1:] {
+			if !mib.symbolOID(sym).Equal(oid) {
+				return nil
+			}
+		}
+	}
+	return defs[0]
+}
@@ -0,0 +1,492 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// OIDFormat selects how FormatOID renders the symbol part of an object ID.
+// The formats correspond to the snmptranslate -O output options.
+type OIDFormat int
+
+// OIDFormat values
+const (
+	OIDModule  OIDFormat = iota // module and name, IF-MIB::ifDescr (-OS)
+	OIDSuffix                   // name only, ifDescr (-Os)
+	OIDFull                     // all names from the root, .iso.org.dod...ifDescr (-Of)
+	OIDNumeric                  // numbers only, .1.3.6.1.2.1.2.2.1.2 (-On)
+)
+
+// IndexFormat selects how FormatOID renders the index of an object instance.
+type IndexFormat int
+
+// IndexFormat values
+const (
+	// IndexNumeric renders the sub-identifiers of the index, as in
+	// vacmGroupName.3.6.112.117.98.108.105.99 (-Ob).
+	IndexNumeric IndexFormat = iota
+
+	// IndexValues decodes the index into the values of the INDEX objects of
+	// the table, as in vacmGroupName.3."public".
+	IndexValues
+
+	// IndexBrackets is like IndexValues, but puts each value in brackets,
+	// as in vacmGroupName[3]["public"] (-OX).
+	IndexBrackets
+)
+
+// FormatOptions controls how FormatOID renders an object ID. The zero value
+// produces the format of SymbolString.
+//
+// Index values are rendered by type: integers as numbers, or as their labels
+// if they are enumerated values, IpAddress values in dotted-quad notation,
+// object IDs in dotted notation and strings in quotes, or as hexadecimal
+// strings such as '0A1B'H if they are not printable.
+// With IndexValues, object IDs keep the length that precedes them in the
+// index. If the index cannot be decoded using the INDEX clause of the table,
+// it is rendered as numbers. MIB.OID accepts all of these formats.
+type FormatOptions struct {
+	OID   OIDFormat
+	Index IndexFormat
+}
+
+// FormatOID returns a string representation of oid in the format selected
+// by opts.
+func (mib *MIB) FormatOID(oid OID, opts FormatOptions) string {
+	if len(oid) == 0 {
+		return ""
+	}
+	if opts.OID == OIDNumeric {
+		return "." + oid.String()
+	}
+	sym, idx := mib.Symbol(oid)
+	if sym == nil {
+		if opts.OID == OIDFull {
+			return "." + oid.String()
+		}
+		return oid.String()
+	}
+
+	var name string
+	switch {
+	case opts.OID == OIDFull:
+		name = symbolPath(sym)
+	case opts.OID == OIDSuffix && sym.Name != "":
+		name = sym.Name
+	case opts.OID == OIDSuffix:
+		name = strconv.Itoa(sym.ID)
+	default:
+		name = sym.String()
+	}
+	if len(idx) == 0 {
+		return name
+	}
+
+	if opts.Index != IndexNumeric && sym.IsColumn() {
+		if values, ok := mib.decodeIndex(sym.Parent, idx, opts.Index == IndexBrackets); ok {
+			if opts.Index == IndexBrackets {
+				return name + "[" + strings.Join(values, "][") + "]"
+			}
+			return name + "." + strings.Join(values, ".")
+		}
+	}
+	return name + "." + idx.String()
+}
+
+// symbolPath returns the names of the symbols from the root of the tree to
+// sym in the format .iso.org.dod. Anonymous symbols are shown by number.
+func symbolPath(sym *Symbol) string {
+	var parts []string
+	for s := sym; s != nil; s = s.Parent {
+		if s.Name == "" {
+			parts = append(parts, strconv.Itoa(s.ID))
+		} else {
+			parts = append(parts, s.Name)
+		}
+	}
+	var b strings.Builder
+	for i := len(parts) - 1; i >= 0; i-- {
+		b.WriteByte('.')
+		b.WriteString(parts[i])
+	}
+	return b.String()
+}
+
+// An indexPart is an object of a table INDEX along with its resolved syntax.
+type indexPart struct {
+	object  *Symbol
+	syntax  Syntax
+	implied bool
+}
+
+// rowIndex returns the objects in the INDEX of the table row, following
+// AUGMENTS to the row that defines the index. It returns nil if the index
+// is not known.
+func (mib *MIB) rowIndex(row *Symbol) []indexPart {
+	for depth := 0; row != nil && row.Node != nil && row.Node.Augments != ""; depth++ {
+		if depth == MaxTypeDepth {
+			return nil
+		}
+		row = mib.lookupSymbol(row.Module, row.Node.Augments)
+	}
+	if row == nil || row.Node == nil || len(row.Node.Index) == 0 {
+		return nil
+	}
+	parts := make([]indexPart, len(row.Node.Index))
+	for i, index := range row.Node.Index {
+		obj := mib.lookupSymbol(row.Module, index.Name)
+		if obj == nil || obj.Node == nil || obj.Node.Syntax == nil {
+			return nil
+		}
+		syntax, ok := mib.resolveSyntax(obj.Module, *obj.Node.Syntax)
+		if !ok {
+			return nil
+		}
+		parts[i] = indexPart{object: obj, syntax: syntax, implied: index.Implied}
+	}
+	return parts
+}
+
+// decodeIndex splits the index of an instance of a column of the table row
+// into the values of the INDEX objects and renders each value by type. Object
+// IDs are only rendered as values if bracketed is true; in dotted form they
+// keep their length so that the index can be parsed again.
+func (mib *MIB) decodeIndex(row *Symbol, idx OID, bracketed bool) ([]string, bool) {
+	parts := mib.rowIndex(row)
+	if parts == nil {
+		return nil, false
+	}
+	values := make([]string, 0, len(parts))
+	for i, part := range parts {
+		last := i == len(parts)-1
+		var n int
+		var value string
+		switch part.syntax.Type {
+		case "INTEGER", "Integer32", "Unsigned32", "Gauge32", "Counter32", "TimeTicks":
+			if len(idx) == 0 {
+				return nil, false
+			}
+			n = 1
+			value = indexLabel(idx[0], part.syntax)
+		case "IpAddress":
+			n = 4
+			b, ok := indexBytes(idx, n)
+			if !ok {
+				return nil, false
+			}
+			value = fmt.Sprintf("%d.%d.%d.%d", b[0], b[1], b[2], b[3])
+		case "OCTET STRING", "Opaque", "BITS":
+			start, length, ok := indexLength(part, idx, last)
+			if !ok {
+				return nil, false
+			}
+			b, ok := indexBytes(idx[start:], length)
+			if !ok {
+				return nil, false
+			}
+			n = start + length
+			value = formatIndexString(b)
+		case "OBJECT IDENTIFIER":
+			start, length, ok := indexLength(part, idx, last)
+			if !ok || length < 0 || start+length > len(idx) {
+				return nil, false
+			}
+			n = start + length
+			if bracketed {
+				value = idx[start:n].String()
+			} else {
+				value = idx[:n].String()
+			}
+		default:
+			return nil, false
+		}
+		if n > len(idx) {
+			return nil, false
+		}
+		values = append(values, value)
+		idx = idx[n:]
+	}
+	return values, len(idx) == 0
+}
+
+// indexLabel renders an integer index value as the name of its enumerated
+// value, or as a number if it has none.
+func indexLabel(n int, syntax Syntax) string {
+	for _, enum := range syntax.Enums {
+		if enum.Value == int64(n) {
+			return enum.Name
+		}
+	}
+	return strconv.Itoa(n)
+}
+
+// indexLength returns the position and length of a variable length index
+// value at the start of idx. The value is preceded by its length unless it
+// has a fixed size or is the IMPLIED last part of the index.
+func indexLength(part indexPart, idx OID, last bool) (int, int, bool) {
+	sizes := part.syntax.Sizes
+	switch {
+	case len(sizes) == 1 && sizes[0].Min == sizes[0].Max:
+		return 0, int(sizes[0].Min), true
+	case part.implied && last:
+		return 0, len(idx), true
+	case len(idx) == 0:
+		return 0, 0, false
+	}
+	return 1, idx[0], true
+}
+
+// indexBytes returns the first n sub-identifiers of idx as bytes.
+func indexBytes(idx OID, n int) ([]byte, bool) {
+	if n < 0 || n > len(idx) {
+		return nil, false
+	}
+	b := make([]byte, n)
+	for i, id := range idx[:n] {
+		if id < 0 || id > 255 {
+			return nil, false
+		}
+		b[i] = byte(id)
+	}
+	return b, true
+}
+
+func formatIndexString(b []byte) string {
+	if isPrintable(b) {
+		return strconv.Quote(string(b))
+	}
+	return fmt.Sprintf("'%X'H", b)
+}
+
+// splitOIDName splits a name in the format accepted by MIB.OID into the parts
+// of its dotted path and the contents of the bracketed index values that follow
+// it. Quoted strings in the path or in brackets may contain dots and brackets.
+func splitOIDName(s string) ([]string, []string, error) {
+	var path, brackets []string
+	i := 0
+	if strings.HasPrefix(s, ".") {
+		i = 1
+	}
+	for i < len(s) && s[i] != '[' {
+		j, err := scanOIDPart(s, i, ".[")
+		if err != nil {
+			return nil, nil, err
+		}
+		if j == i {
+			return nil, nil, fmt.Errorf("empty sub-identifier in %q", s)
+		}
+		path = append(path, s[i:j])
+		i = j
+		if i < len(s) && s[i] == '.' {
+			i++
+			if i == len(s) {
+				return nil, nil, fmt.Errorf("empty sub-identifier in %q", s)
+			}
+		}
+	}
+	for i < len(s) {
+		if s[i] != '[' {
+			return nil, nil, fmt.Errorf("unexpected %q after index in %q", s[i:], s)
+		}
+		j, err := scanOIDPart(s, i+1, "]")
+		if err != nil {
+			return nil, nil, err
+		}
+		if j == len(s) {
+			return nil, nil, fmt.Errorf("missing ] in %q", s)
+		}
+		brackets = append(brackets, s[i+1:j])
+		i = j + 1
+	}
+	return path, brackets, nil
+}
+
+// scanOIDPart returns the position of the first byte from stops at or after
+// position i of s, skipping over quoted strings.
+func scanOIDPart(s string, i int, stops string) (int, error) {
+	for i < len(s) && strings.IndexByte(stops, s[i]) == -1 {
+		if q := s[i]; q == '"' || q == '\'' {
+			for i++; i < len(s) && s[i] != q; i++ {
+				if s[i] == '\\' && q == '"' {
+					i++
+				}
+			}
+			if i >= len(s) {
+				return 0, fmt.Errorf("unterminated string in %q", s)
+			}
+		}
+		i++
+	}
+	return i, nil
+}
+
+func isNumber(s string) bool {
+	_, err := parseSubID(s)
+	return err == nil
+}
+
+// parseNumericIndex converts an index given as numbers.
+func parseNumericIndex(values []string) (OID, error) {
+	var idx OID
+	for _, value := range values {
+		n, err := parseSubID(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid index number %s", value)
+		}
+		idx = append(idx, n)
+	}
+	return idx, nil
+}
+
+// encodeIndex converts the index values of an instance of the column sym to
+// sub-identifiers according to the INDEX clause of the table. If bracketed is
+// true, each value comes from a pair of brackets. Otherwise the values are the
+// dotted parts of the name, so an IpAddress takes up four values and an object
+// ID is given as numbers.
+func (mib *MIB) encodeIndex(sym *Symbol, values []string, bracketed bool) (OID, error) {
+	if sym == nil || !sym.IsColumn() {
+		if !bracketed && len(values) > 0 && isLabel(values[0]) {
+			if sym == nil {
+				return nil, fmt.Errorf("name %s not found", values[0])
+			}
+			return nil, fmt.Errorf("name %s not found below %s", values[0], sym)
+		}
+		if sym == nil {
+			return nil, fmt.Errorf("cannot convert index %s without a table column", strings.Join(values, "."))
+		}
+		return nil, fmt.Errorf("cannot convert index of %s, not a table column", sym)
+	}
+	parts := mib.rowIndex(sym.Parent)
+	if parts == nil {
+		return nil, fmt.Errorf("cannot convert index of %s, INDEX types not known", sym)
+	}
+
+	var idx OID
+	for i, part := range parts {
+		last := i == len(parts)-1
+		if len(values) == 0 {
+			return nil, fmt.Errorf("missing value for index %s of %s", part.object.Name, sym)
+		}
+		value := values[0]
+		values = values[1:]
+		var err error
+		switch part.syntax.Type {
+		case "INTEGER", "Integer32", "Unsigned32", "Gauge32", "Counter32", "TimeTicks":
+			var n int
+			n, err = indexNumber(value, part.syntax)
+			idx = append(idx, n)
+		case "IpAddress":
+			if !bracketed {
+				if len(values) < 3 {
+					return nil, fmt.Errorf("invalid IpAddress for index %s of %s", part.object.Name, sym)
+				}
+				value = strings.Join(append([]string{value}, values[:3]...), ".")
+				values = values[3:]
+			}
+			ip := net.ParseIP(value).To4()
+			if ip == nil || strings.Contains(value, ":") {
+				err = fmt.Errorf("invalid IpAddress %s", value)
+			}
+			for _, b := range ip {
+				idx = append(idx, int(b))
+			}
+		case "OCTET STRING", "Opaque", "BITS":
+			var b []byte
+			b, err = indexString(value)
+			if err != nil {
+				break
+			}
+			sizes := part.syntax.Sizes
+			switch {
+			case len(sizes) == 1 && sizes[0].Min == sizes[0].Max:
+				if int64(len(b)) != sizes[0].Min {
+					err = fmt.Errorf("index value %s must be %d bytes", value, sizes[0].Min)
+				}
+			case !(part.implied && last):
+				idx = append(idx, len(b))
+			}
+			for _, c := range b {
+				idx = append(idx, int(c))
+			}
+		case "OBJECT IDENTIFIER":
+			if !bracketed {
+				// Dotted object IDs are given as numbers, including the length
+				n := len(values)
+				if !(part.implied && last) {
+					length, err := parseSubID(value)
+					if err != nil || length > len(values) {
+						return nil, fmt.Errorf("index %s of %s: invalid object ID", part.object.Name, sym)
+					}
+					n = length
+				}
+				var oid OID
+				oid, err = parseNumericIndex(append([]string{value}, values[:n]...))
+				values = values[n:]
+				idx = append(idx, oid...)
+				break
+			}
+			var oid OID
+			oid, err = mib.OID(value)
+			if !(part.implied && last) {
+				idx = append(idx, len(oid))
+			}
+			idx = append(idx, oid...)
+		default:
+			err = fmt.Errorf("unsupported type %s", part.syntax.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("index %s of %s: %v", part.object.Name, sym, err)
+		}
+	}
+	if len(values) > 0 {
+		return nil, fmt.Errorf("too many index values for %s: %s", sym, strings.Join(values, ", "))
+	}
+	return idx, nil
+}
+
+// isLabel returns true if s could be the label of a symbol.
+func isLabel(s string) bool {
+	return s != "" && isLetterByte(s[0])
+}
+
+// indexNumber converts an integer index value, which may be a number or the
+// name of an enumerated value.
+func indexNumber(value string, syntax Syntax) (int, error) {
+	if n, err := parseSubID(value); err == nil {
+		return n, nil
+	}
+	for _, enum := range syntax.Enums {
+		if enum.Name == value && enum.Value >= 0 {
+			return int(enum.Value), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid number %s", value)
+}
+
+// indexString converts a string index value, which is either quoted or a
+// hexadecimal string such as '0A1B'H.
+func indexString(value string) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(value, `"`):
+		s, err := strconv.Unquote(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid string %s", value)
+		}
+		return []byte(s), nil
+	case len(value) >= 3 && value[0] == '\'' && strings.HasSuffix(strings.ToUpper(value), "'H"):
+		b, err := hex.DecodeString(value[1 : len(value)-2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid hexadecimal string %s", value)
+		}
+		return b, nil
+	}
+	return nil, fmt.Errorf("string %s must be quoted", value)
+}
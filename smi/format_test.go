@@ -0,0 +1,396 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hallidave/mibtool/smi"
+)
+
+const indexTestMIB = `INDEX-TEST-MIB DEFINITIONS ::= BEGIN
+IMPORTS
+    OBJECT-TYPE, Integer32, IpAddress, enterprises FROM SNMPv2-SMI
+    TEXTUAL-CONVENTION, MacAddress FROM SNMPv2-TC;
+
+indexTest OBJECT IDENTIFIER ::= { enterprises 7777 }
+
+AdminString ::= TEXTUAL-CONVENTION
+    DISPLAY-HINT "255t"
+    STATUS       current
+    DESCRIPTION  "A name."
+    SYNTAX       OCTET STRING (SIZE (0..32))
+
+groupTable OBJECT-TYPE
+    SYNTAX      SEQUENCE OF GroupEntry
+    MAX-ACCESS  not-accessible
+    STATUS      current
+    DESCRIPTION "Groups."
+    ::= { indexTest 1 }
+
+groupEntry OBJECT-TYPE
+    SYNTAX      GroupEntry
+    MAX-ACCESS  not-accessible
+    STATUS      current
+    DESCRIPTION "A group."
+    INDEX       { groupModel, groupName }
+    ::= { groupTable 1 }
+
+GroupEntry ::= SEQUENCE {
+    groupModel  Integer32,
+    groupName   AdminString,
+    groupView   OBJECT IDENTIFIER
+}
+
+groupModel OBJECT-TYPE
+    SYNTAX      Integer32 (0..255)
+    MAX-ACCESS  not-accessible
+    STATUS      current
+    DESCRIPTION "The model."
+    ::= { groupEntry 1 }
+
+groupName OBJECT-TYPE
+    SYNTAX      AdminString
+    MAX-ACCESS  not-accessible
+    STATUS      current
+    DESCRIPTION "The name."
+    ::= { groupEntry 2 }
+
+groupView OBJECT-TYPE
+    SYNTAX      OBJECT IDENTIFIER
+    MAX-ACCESS  read-only
+    STATUS      current
+    DESCRIPTION "The view."
+    ::= { groupEntry 3 }
+
+groupExtEntry OBJECT-TYPE
+    SYNTAX      GroupExtEntry
+    MAX-ACCESS  not-accessible
+    STATUS      current
+    DESCRIPTION "More about a group."
+    AUGMENTS    { groupEntry }
+    ::= { indexTest 2 }
+
+GroupExtEntry ::= SEQUENCE {
+    groupComment  AdminString
+}
+
+groupComment OBJECT-TYPE
+    SYNTAX      AdminString
+    MAX-ACCESS  read-only
+    STATUS      current
+    DESCRIPTION "A comment."
+    ::= { groupExtEntry 1 }
+
+hostTable OBJECT-TYPE
+    SYNTAX      SEQUENCE OF HostEntry
+    MAX-ACCESS  not-accessible
+    STATUS      current
+    DESCRIPTION "Hosts."
+    ::= { indexTest 3 }
+
+hostEntry OBJECT-TYPE
+    SYNTAX      HostEntry
+    MAX-ACCESS  not-accessible
+    STATUS      current
+    DESCRIPTION "A host."
+    INDEX       { hostAddress, hostMac, IMPLIED hostName }
+    ::= { hostTable 1 }
+
+HostEntry ::= SEQUENCE {
+    hostAddress  IpAddress,
+    hostMac      MacAddress,
+    hostName     OCTET STRING,
+    hostPolicy   OBJECT IDENTIFIER
+}
+
+hostAddress OBJECT-TYPE
+    SYNTAX      IpAddress
+    MAX-ACCESS  not-accessible
+    STATUS      current
+    DESCRIPTION "The address."
+    ::= { hostEntry 1 }
+
+hostMac OBJECT-TYPE
+    SYNTAX      MacAddress
+    MAX-ACCESS  not-accessible
+    STATUS      current
+    DESCRIPTION "The MAC address."
+    ::= { hostEntry 2 }
+
+hostName OBJECT-TYPE
+    SYNTAX      OCTET STRING
+    MAX-ACCESS  not-accessible
+    STATUS      current
+    DESCRIPTION "The name."
+    ::= { hostEntry 3 }
+
+hostPolicy OBJECT-TYPE
+    SYNTAX      OBJECT IDENTIFIER
+    MAX-ACCESS  read-only
+    STATUS      current
+    DESCRIPTION "The policy."
+    ::= { hostEntry 4 }
+
+policyTable OBJECT-TYPE
+    SYNTAX      SEQUENCE OF PolicyEntry
+    MAX-ACCESS  not-accessible
+    STATUS      current
+    DESCRIPTION "Policies."
+    ::= { indexTest 4 }
+
+policyEntry OBJECT-TYPE
+    SYNTAX      PolicyEntry
+    MAX-ACCESS  not-accessible
+    STATUS      current
+    DESCRIPTION "A policy."
+    INDEX       { policyID, policyRank }
+    ::= { policyTable 1 }
+
+PolicyEntry ::= SEQUENCE {
+    policyID     OBJECT IDENTIFIER,
+    policyRank   Integer32
+}
+
+policyID OBJECT-TYPE
+    SYNTAX      OBJECT IDENTIFIER
+    MAX-ACCESS  not-accessible
+    STATUS      current
+    DESCRIPTION "The policy ID."
+    ::= { policyEntry 1 }
+
+policyRank OBJECT-TYPE
+    SYNTAX      Integer32
+    MAX-ACCESS  read-only
+    STATUS      current
+    DESCRIPTION "The rank."
+    ::= { policyEntry 2 }
+
+mediaTable OBJECT-TYPE
+    SYNTAX      SEQUENCE OF MediaEntry
+    MAX-ACCESS  not-accessible
+    STATUS      current
+    DESCRIPTION "Media."
+    ::= { indexTest 5 }
+
+mediaEntry OBJECT-TYPE
+    SYNTAX      MediaEntry
+    MAX-ACCESS  not-accessible
+    STATUS      current
+    DESCRIPTION "A medium."
+    INDEX       { mediaType }
+    ::= { mediaTable 1 }
+
+MediaEntry ::= SEQUENCE {
+    mediaType   INTEGER,
+    mediaSpeed  Integer32
+}
+
+mediaType OBJECT-TYPE
+    SYNTAX      INTEGER { copper(1), fiber(2) }
+    MAX-ACCESS  not-accessible
+    STATUS      current
+    DESCRIPTION "The type of medium."
+    ::= { mediaEntry 1 }
+
+mediaSpeed OBJECT-TYPE
+    SYNTAX      Integer32
+    MAX-ACCESS  read-only
+    STATUS      current
+    DESCRIPTION "The speed."
+    ::= { mediaEntry 2 }
+
+END
+`
+
+func setupIndexDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "mibtool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"SNMPv2-SMI", "SNMPv2-TC"} {
+		text, err := ioutil.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeMIBFile(t, dir, name, text)
+	}
+	writeMIBFile(t, dir, "INDEX-TEST-MIB", []byte(indexTestMIB))
+	return dir
+}
+
+func TestFormatOID(t *testing.T) {
+	mib := smi.NewMIB("testdata")
+	err := mib.LoadModules("IF-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ifDescr3 := smi.OID{1, 3, 6, 1, 2, 1, 2, 2, 1, 2, 3}
+	ifStackStatus := smi.OID{1, 3, 6, 1, 2, 1, 31, 1, 2, 1, 3, 1, 2}
+	tests := []struct {
+		oid      smi.OID
+		opts     smi.FormatOptions
+		expected string
+	}{
+		{ifDescr3, smi.FormatOptions{}, "IF-MIB::ifDescr.3"},
+		{ifDescr3, smi.FormatOptions{OID: smi.OIDSuffix}, "ifDescr.3"},
+		{ifDescr3, smi.FormatOptions{OID: smi.OIDNumeric}, ".1.3.6.1.2.1.2.2.1.2.3"},
+		{ifDescr3, smi.FormatOptions{OID: smi.OIDNumeric, Index: smi.IndexBrackets}, ".1.3.6.1.2.1.2.2.1.2.3"},
+		{ifDescr3, smi.FormatOptions{OID: smi.OIDFull},
+			".iso.org.dod.internet.mgmt.mib-2.interfaces.ifTable.ifEntry.ifDescr.3"},
+		{ifDescr3, smi.FormatOptions{Index: smi.IndexValues}, "IF-MIB::ifDescr.3"},
+		{ifDescr3, smi.FormatOptions{OID: smi.OIDSuffix, Index: smi.IndexBrackets}, "ifDescr[3]"},
+		{ifStackStatus, smi.FormatOptions{OID: smi.OIDSuffix, Index: smi.IndexBrackets}, "ifStackStatus[1][2]"},
+		{ifStackStatus[:12], smi.FormatOptions{OID: smi.OIDSuffix, Index: smi.IndexBrackets}, "ifStackStatus.1"},
+		{smi.OID{1, 3, 6, 1, 2, 1, 2, 2, 1}, smi.FormatOptions{OID: smi.OIDSuffix}, "ifEntry"},
+		{smi.OID{1, 3, 6, 1, 2, 1, 2, 1, 0}, smi.FormatOptions{OID: smi.OIDSuffix, Index: smi.IndexBrackets}, "ifNumber.0"},
+		{smi.OID{1, 1}, smi.FormatOptions{OID: smi.OIDFull}, ".iso.1"},
+		{smi.OID{2, 5}, smi.FormatOptions{OID: smi.OIDFull}, ".2.5"},
+		{smi.OID{}, smi.FormatOptions{OID: smi.OIDNumeric}, ""},
+	}
+	for _, test := range tests {
+		result := mib.FormatOID(test.oid, test.opts)
+		if result != test.expected {
+			t.Errorf("%s %+v: got %s, expected %s", test.oid, test.opts, result, test.expected)
+		}
+	}
+}
+
+func TestFormatIndexValues(t *testing.T) {
+	dir := setupIndexDir(t)
+	defer os.RemoveAll(dir)
+
+	mib := smi.NewMIB(dir)
+	err := mib.LoadModules("INDEX-TEST-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := smi.FormatOptions{OID: smi.OIDSuffix, Index: smi.IndexValues}
+	brackets := smi.FormatOptions{OID: smi.OIDSuffix, Index: smi.IndexBrackets}
+	groupView := smi.OID{1, 3, 6, 1, 4, 1, 7777, 1, 1, 3}
+	groupComment := smi.OID{1, 3, 6, 1, 4, 1, 7777, 2, 1}
+	hostPolicy := smi.OID{1, 3, 6, 1, 4, 1, 7777, 3, 1, 4}
+	policyRank := smi.OID{1, 3, 6, 1, 4, 1, 7777, 4, 1, 2}
+	mediaSpeed := smi.OID{1, 3, 6, 1, 4, 1, 7777, 5, 1, 2}
+	tests := []struct {
+		oid      smi.OID
+		opts     smi.FormatOptions
+		expected string
+	}{
+		{groupView.Append(3, 6, 112, 117, 98, 108, 105, 99), values, `groupView.3."public"`},
+		{groupView.Append(3, 6, 112, 117, 98, 108, 105, 99), brackets, `groupView[3]["public"]`},
+		{groupView.Append(3, 6, 112, 117, 98, 108, 105, 99), smi.FormatOptions{OID: smi.OIDSuffix},
+			"groupView.3.6.112.117.98.108.105.99"},
+		{groupView.Append(3, 0), brackets, `groupView[3][""]`},
+		{groupView.Append(3, 2, 1, 255), brackets, `groupView[3]['01FF'H]`},
+		{groupComment.Append(1, 1, 97), brackets, `groupComment[1]["a"]`},
+		{hostPolicy.Append(10, 0, 0, 1, 0, 17, 34, 51, 68, 85, 104, 111, 115, 116),
+			brackets, `hostPolicy[10.0.0.1]['001122334455'H]["host"]`},
+		{hostPolicy.Append(10, 0, 0, 1, 0, 17, 34, 51, 68, 85), brackets, `hostPolicy[10.0.0.1]['001122334455'H][""]`},
+		{policyRank.Append(3, 1, 3, 6, 5), values, "policyRank.3.1.3.6.5"},
+		{policyRank.Append(3, 1, 3, 6, 5), brackets, "policyRank[1.3.6][5]"},
+		{mediaSpeed.Append(2), values, "mediaSpeed.fiber"},
+		{mediaSpeed.Append(1), brackets, "mediaSpeed[copper]"},
+		{mediaSpeed.Append(7), brackets, "mediaSpeed[7]"},
+
+		// Indexes that do not match the INDEX clause are shown as numbers
+		{groupView.Append(3, 6, 112), brackets, "groupView.3.6.112"},
+		{groupView.Append(3, 1, 256), brackets, "groupView.3.1.256"},
+		{groupView.Append(3, 1, 97, 5), brackets, "groupView.3.1.97.5"},
+		{hostPolicy.Append(10, 0, 256, 1, 0, 17, 34, 51, 68, 85), brackets, "hostPolicy.10.0.256.1.0.17.34.51.68.85"},
+		{policyRank.Append(9, 1, 3), brackets, "policyRank.9.1.3"},
+	}
+	for _, test := range tests {
+		result := mib.FormatOID(test.oid, test.opts)
+		if result != test.expected {
+			t.Errorf("%s: got %s, expected %s", test.oid, result, test.expected)
+		}
+	}
+}
+
+func TestOIDIndexValues(t *testing.T) {
+	dir := setupIndexDir(t)
+	defer os.RemoveAll(dir)
+
+	mib := smi.NewMIB(dir)
+	err := mib.LoadModules("INDEX-TEST-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	groupView := smi.OID{1, 3, 6, 1, 4, 1, 7777, 1, 1, 3}
+	hostPolicy := smi.OID{1, 3, 6, 1, 4, 1, 7777, 3, 1, 4}
+	policyRank := smi.OID{1, 3, 6, 1, 4, 1, 7777, 4, 1, 2}
+	mediaSpeed := smi.OID{1, 3, 6, 1, 4, 1, 7777, 5, 1, 2}
+	tests := []struct {
+		in       string
+		expected smi.OID
+	}{
+		{`groupView.3."public"`, groupView.Append(3, 6, 112, 117, 98, 108, 105, 99)},
+		{`groupView[3]["public"]`, groupView.Append(3, 6, 112, 117, 98, 108, 105, 99)},
+		{`INDEX-TEST-MIB::groupView[3]["a.b[c]"]`, groupView.Append(3, 6, 97, 46, 98, 91, 99, 93)},
+		{`groupView[3]['01ff'H]`, groupView.Append(3, 2, 1, 255)},
+		{`groupView.3.""`, groupView.Append(3, 0)},
+		{`groupComment[1]["a"]`, smi.OID{1, 3, 6, 1, 4, 1, 7777, 2, 1, 1, 1, 97}},
+		{`hostPolicy.10.0.0.1.'001122334455'H."host"`,
+			hostPolicy.Append(10, 0, 0, 1, 0, 17, 34, 51, 68, 85, 104, 111, 115, 116)},
+		{`hostPolicy[10.0.0.1]['001122334455'H][""]`, hostPolicy.Append(10, 0, 0, 1, 0, 17, 34, 51, 68, 85)},
+		{`policyRank[1.3.6][5]`, policyRank.Append(3, 1, 3, 6, 5)},
+		{`policyRank.3.1.3.6.5`, policyRank.Append(3, 1, 3, 6, 5)},
+		{`hostPolicy.10.0.0.1.'001122334455'H.'00'H`, hostPolicy.Append(10, 0, 0, 1, 0, 17, 34, 51, 68, 85, 0)},
+		{`policyRank[indexTest][5]`, policyRank.Append(7, 1, 3, 6, 1, 4, 1, 7777, 5)},
+		{`mediaSpeed.fiber`, mediaSpeed.Append(2)},
+		{`mediaSpeed[copper]`, mediaSpeed.Append(1)},
+		{`groupView.3.public`, nil},
+		{`groupView[3]["public"][4]`, nil},
+		{`groupView[3]`, nil},
+		{`groupView[x]["public"]`, nil},
+		{`groupView.3."public`, nil},
+		{`hostPolicy[10.0.0]['001122334455'H][""]`, nil},
+		{`hostPolicy[10.0.0.1]['0011'H][""]`, nil},
+		{`policyRank.1.3.6.5.x`, nil},
+	}
+	for _, test := range tests {
+		result, err := mib.OID(test.in)
+		if err != nil && test.expected != nil {
+			t.Errorf("%s: %v", test.in, err)
+		}
+		if err == nil && test.expected == nil {
+			t.Errorf("%s: expected error, got %s", test.in, result)
+		}
+		if !result.Equal(test.expected) {
+			t.Errorf("%s: got %s, expected %s", test.in, result, test.expected)
+		}
+	}
+
+	// Every format can be parsed again
+	for _, oid := range []smi.OID{
+		groupView.Append(3, 6, 112, 117, 98, 108, 105, 99),
+		groupView.Append(3, 2, 1, 255),
+		hostPolicy.Append(10, 0, 0, 1, 0, 17, 34, 51, 68, 85, 104, 111, 115, 116),
+		policyRank.Append(3, 1, 3, 6, 5),
+	} {
+		for _, opts := range []smi.FormatOptions{
+			{OID: smi.OIDModule, Index: smi.IndexNumeric},
+			{OID: smi.OIDSuffix, Index: smi.IndexValues},
+			{OID: smi.OIDFull, Index: smi.IndexBrackets},
+			{OID: smi.OIDNumeric},
+		} {
+			name := mib.FormatOID(oid, opts)
+			result, err := mib.OID(name)
+			if err != nil {
+				t.Errorf("%s: %v", name, err)
+			} else if !result.Equal(oid) {
+				t.Errorf("%s: got %s, expected %s", name, result, oid)
+			}
+		}
+	}
+}
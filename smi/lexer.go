@@ -6,7 +6,7 @@
 package smi
 
 import (
-	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"math"
@@ -115,17 +115,21 @@ var keywords = map[string]int{
 	"WRITE-SYNTAX":       tWRITE_SYNTAX,
 }
 
-// Lexer holds the current state of the lexer
+// Lexer holds the current state of the lexer. The input is read into a
+// single buffer and tokens are scanned in place, so that only the
+// identifiers and strings passed to the parser are copied.
 type Lexer struct {
-	s           *bufio.Scanner
-	line        []byte
-	lineno      int
-	scanOffset  int
-	tokenOffset int
-	state       skipState
-	savedToken  *string
-	err         error
-	module      *Module
+	r         io.Reader
+	buf       []byte
+	offset    int // scanning position in buf
+	lineStart int // offset of the current line in buf
+	tokenEnd  int // offset of the end of the last token in buf
+	lineno    int
+	state     skipState
+	err       error
+	module    *Module
+	types     []Type
+	names     map[string]string
 }
 
 func init() {
@@ -153,79 +157,99 @@ func fixToknames() {
 	}
 }
 
-func (lex *Lexer) readLine() bool {
-	if lex.err != nil {
+// lexReadSize is the size of the first read from the input. The buffer
+// doubles in size whenever it is full, so that reading the start of a file,
+// as ModuleName does, is cheap while a whole module still takes few reads.
+const lexReadSize = 4096
+
+// fill reads more of the input into the buffer. It returns false if there is
+// no more input, either at the end of the input or after an error.
+func (lex *Lexer) fill() bool {
+	if lex.r == nil || lex.err != nil {
 		return false
 	}
-	if !lex.s.Scan() {
-		lex.err = lex.s.Err()
-		return false
+	if len(lex.buf) == cap(lex.buf) {
+		buf := make([]byte, len(lex.buf), max(2*cap(lex.buf), lexReadSize))
+		copy(buf, lex.buf)
+		lex.buf = buf
+	}
+	n, err := lex.r.Read(lex.buf[len(lex.buf):cap(lex.buf)])
+	lex.buf = lex.buf[:len(lex.buf)+n]
+	if err != nil {
+		if err != io.EOF {
+			lex.err = err
+		}
+		lex.r = nil
+		return n > 0
 	}
-	lex.line = lex.s.Bytes()
-	lex.tokenOffset = 0
-	lex.scanOffset = 0
-	lex.lineno++
 	return true
 }
 
-func (lex *Lexer) peek() byte {
-	if lex.line == nil {
-		if !lex.readLine() {
+// peekAt returns the byte n bytes past the scanning position without
+// consuming it, or lexEOF at the end of the input.
+func (lex *Lexer) peekAt(n int) byte {
+	for lex.offset+n >= len(lex.buf) {
+		if !lex.fill() {
 			return lexEOF
 		}
 	}
-	if lex.line != nil && lex.scanOffset == len(lex.line) {
-		s := lex.consumeToken()
-		lex.savedToken = &s
-		lex.line = nil
-		return lexEOL
-	}
-	if lex.savedToken != nil {
-		return lexEOL
-	}
-	return lex.line[lex.scanOffset]
-}
-
-func (lex *Lexer) peek2() []byte {
-	b2 := make([]byte, 2)
-	b2[0] = lex.peek()
-	if lex.scanOffset < len(lex.line)-1 {
-		b := lex.line[lex.scanOffset+1]
-		b2[1] = b
-	}
-	return b2
+	return lex.buf[lex.offset+n]
 }
 
-func (lex *Lexer) next() byte {
-	b := lex.peek()
-	if b != lexEOL && b != lexEOF {
-		lex.scanOffset++
+func (lex *Lexer) peek() byte {
+	if lex.offset < len(lex.buf) {
+		return lex.buf[lex.offset]
 	}
-	return b
+	return lex.peekAt(0)
 }
 
-func (lex *Lexer) skip(n int) {
-	lex.scanOffset += n
-	lex.tokenOffset = lex.scanOffset
-	lex.savedToken = nil
+// newline consumes the end of line character at the scanning position.
+func (lex *Lexer) newline() {
+	lex.offset++
+	lex.lineno++
+	lex.lineStart = lex.offset
 }
 
-func (lex *Lexer) skipWhitespace() {
-	for b := lex.peek(); isSpaceByte(b); b = lex.peek() {
-		lex.skip(1)
+// skipSpace skips white space and comments. A carriage return is only white
+// space at the end of a line.
+func (lex *Lexer) skipSpace() {
+	for {
+		switch b := lex.peek(); b {
+		case ' ', '\t':
+			lex.offset++
+		case lexEOL:
+			lex.newline()
+		case '\r':
+			if b1 := lex.peekAt(1); b1 != lexEOL && b1 != lexEOF {
+				return
+			}
+			lex.offset++
+		case '-':
+			if lex.peekAt(1) != '-' {
+				return
+			}
+			lex.skipComment()
+		default:
+			return
+		}
 	}
 }
 
-func (lex *Lexer) consumeToken() string {
-	if lex.savedToken != nil {
-		ret := lex.savedToken
-		lex.savedToken = nil
-		return *ret
+// skipComment skips a comment, which ends at the end of the line or at the
+// next pair of dashes.
+func (lex *Lexer) skipComment() {
+	lex.offset += 2
+	for {
+		switch b := lex.peek(); {
+		case b == lexEOL || b == lexEOF:
+			return
+		case b == '-' && lex.peekAt(1) == '-':
+			lex.offset += 2
+			return
+		default:
+			lex.offset++
+		}
 	}
-	tokStr := string(lex.line[lex.tokenOffset:lex.scanOffset])
-	lex.tokenOffset = lex.scanOffset
-
-	return tokStr
 }
 
 func isLetterByte(b byte) bool {
@@ -248,20 +272,17 @@ func isLowerByte(b byte) bool {
 	return b <= unicode.MaxASCII && unicode.IsLower(rune(b))
 }
 
-func isIdentByte(b2 []byte) bool {
-	return isLetterByte(b2[0]) || isDigitByte(b2[0]) ||
-		(b2[0] == '-' && (isLetterByte(b2[1]) || isDigitByte(b2[1])))
-}
-
-func isSpaceByte(b byte) bool {
-	return b == ' ' || b == '\t' || b == lexEOL
+func isIdentByte(b, next byte) bool {
+	return isLetterByte(b) || isDigitByte(b) ||
+		(b == '-' && (isLetterByte(next) || isDigitByte(next)))
 }
 
 func (lex *Lexer) consumeIdent(lval *smiSymType) int {
-	for b2 := lex.peek2(); isIdentByte(b2); b2 = lex.peek2() {
-		lex.next()
+	start := lex.offset
+	for b := lex.peek(); isIdentByte(b, lex.peekAt(1)); b = lex.peek() {
+		lex.offset++
 	}
-	lval.id = lex.consumeToken()
+	lval.id = lex.intern(lex.buf[start:lex.offset])
 
 	if tok, ok := keywords[lval.id]; ok {
 		return tok
@@ -274,63 +295,59 @@ func (lex *Lexer) consumeIdent(lval *smiSymType) int {
 	return tok
 }
 
-func (lex *Lexer) consumeDash(lval *smiSymType) int {
-	b2 := lex.peek2()
-	if b2[0] == '-' && b2[1] == '-' {
-		return lex.consumeComment(lval)
+// intern returns the first identifier equal to name seen by the lexer, so
+// that the parse results of a module, in which names such as the parent of
+// each table column are repeated many times, hold a single copy of each.
+// Looking up a name that has been seen before does not allocate.
+func (lex *Lexer) intern(name []byte) string {
+	if s, ok := lex.names[string(name)]; ok {
+		return s
 	}
-	if b2[0] == '-' && isDigitByte(b2[1]) {
-		return lex.consumeSigned(lval)
+	if lex.names == nil {
+		lex.names = make(map[string]string)
 	}
-	tok := int(lex.next())
-	lex.consumeToken()
-	return tok
+	s := string(name)
+	lex.names[s] = s
+	return s
 }
 
-func (lex *Lexer) consumeComment(lval *smiSymType) int {
-	lex.skip(2)
-	for {
-		b2 := lex.peek2()
-		if b2[0] == lexEOL || b2[0] == lexEOF {
-			lex.skip(1)
-			break
-		}
-		if b2[0] == '-' && b2[1] == '-' {
-			lex.skip(2)
-			break
-		}
-		lex.skip(1)
+func (lex *Lexer) consumeDash(lval *smiSymType) int {
+	if isDigitByte(lex.peekAt(1)) {
+		return lex.consumeSigned(lval)
 	}
-	return lex.Lex(lval)
+	lex.offset++
+	return '-'
 }
 
 func (lex *Lexer) consumeSingleQuote(lval *smiSymType) int {
 	binOnly := true
 
-	lex.skip(1)
+	lex.offset++
+	start := lex.offset
 	for b := lex.peek(); b != '\''; b = lex.peek() {
-		d := lex.next()
-		if d == lexEOF {
+		if b == lexEOF {
 			lex.err = fmt.Errorf("file ends with unterminated numeric string")
 			return lexEOF
 		}
-		if !isHexDigitByte(d) {
+		if !isHexDigitByte(b) {
 			lex.err = fmt.Errorf("expected a digit")
 			return lexEOF
 		}
-		if binOnly && !isBinaryDigitByte(d) {
+		if binOnly && !isBinaryDigitByte(b) {
 			binOnly = false
 		}
+		lex.offset++
 	}
-	lval.text = lex.consumeToken()
-	lex.skip(1)
+	lval.text = string(lex.buf[start:lex.offset])
+	lex.offset++
 
 	t := lex.peek()
-	lex.skip(1)
 	if t == 'h' || t == 'H' {
+		lex.offset++
 		return tHEX_STRING
 	}
 	if t == 'b' || t == 'B' {
+		lex.offset++
 		if binOnly {
 			return tBIN_STRING
 		}
@@ -343,8 +360,8 @@ func (lex *Lexer) consumeSingleQuote(lval *smiSymType) int {
 }
 
 func (lex *Lexer) consumeDoubleQuote(lval *smiSymType) int {
-	buf := strings.Builder{}
-	lex.skip(1)
+	lex.offset++
+	start := lex.offset
 	for {
 		b := lex.peek()
 		if b == lexEOF {
@@ -352,34 +369,47 @@ func (lex *Lexer) consumeDoubleQuote(lval *smiSymType) int {
 			return lexEOF
 		}
 		if b == '"' {
-			buf.WriteString(lex.consumeToken())
-			lval.text = buf.String()
-			lex.skip(1)
 			break
 		}
 		if b == lexEOL {
-			buf.WriteString(lex.consumeToken())
-			buf.WriteByte('\n')
+			lex.newline()
 			continue
 		}
-		lex.next()
+		lex.offset++
 	}
+	text := lex.buf[start:lex.offset]
+	if bytes.IndexByte(text, '\r') >= 0 {
+		lval.text = strings.ReplaceAll(string(text), "\r\n", "\n")
+	} else {
+		lval.text = string(text)
+	}
+	lex.offset++
 	return tQUOTED_STRING
 }
 
-func (lex *Lexer) consumeUnsigned(lval *smiSymType) int {
+// scanDigits consumes a run of decimal digits and returns its value. The
+// second result is false if the value does not fit in a uint64.
+func (lex *Lexer) scanDigits() (uint64, bool) {
+	var n uint64
+	ok := true
 	for b := lex.peek(); isDigitByte(b); b = lex.peek() {
-		lex.next()
+		d := uint64(b - '0')
+		if n > (math.MaxUint64-d)/10 {
+			ok = false
+		}
+		n = n*10 + d
+		lex.offset++
 	}
-	text := lex.consumeToken()
-	i, err := strconv.ParseUint(text, 10, 64)
-	if err != nil {
-		lex.err = err
+	return n, ok
+}
+
+func (lex *Lexer) consumeUnsigned(lval *smiSymType) int {
+	start := lex.offset
+	i, ok := lex.scanDigits()
+	if !ok {
+		_, lex.err = strconv.ParseUint(string(lex.buf[start:lex.offset]), 10, 64)
 		return lexEOF
 	}
-	if i < 0 {
-		panic("expected positive number")
-	}
 	if i <= uint64(math.MaxUint32) {
 		lval.unsigned32 = uint32(i)
 		return tNUMBER
@@ -389,20 +419,15 @@ func (lex *Lexer) consumeUnsigned(lval *smiSymType) int {
 }
 
 func (lex *Lexer) consumeSigned(lval *smiSymType) int {
-	lex.next()
-	for b := lex.peek(); isDigitByte(b); b = lex.peek() {
-		lex.next()
-	}
-	text := lex.consumeToken()
-	i, err := strconv.ParseInt(text, 10, 64)
-	if err != nil {
-		lex.err = err
+	start := lex.offset
+	lex.offset++
+	n, ok := lex.scanDigits()
+	if !ok || n > -math.MinInt64 {
+		_, lex.err = strconv.ParseInt(string(lex.buf[start:lex.offset]), 10, 64)
 		return lexEOF
 	}
-	if i > 0 {
-		panic("expected negative number")
-	}
-	if i <= int64(math.MaxInt32) {
+	i := -int64(n)
+	if i >= math.MinInt32 {
 		lval.integer32 = int32(i)
 		return tNEGATIVENUMBER
 	}
@@ -411,49 +436,52 @@ func (lex *Lexer) consumeSigned(lval *smiSymType) int {
 }
 
 func (lex *Lexer) consumeColon(lval *smiSymType) int {
-	b := lex.next()
-	if b2 := lex.peek2(); b2[0] == ':' && b2[1] == '=' {
-		lex.skip(2)
+	lex.offset++
+	if lex.peek() == ':' && lex.peekAt(1) == '=' {
+		lex.offset += 2
 		return tCOLON_COLON_EQUAL
 	}
-	return int(b)
+	return ':'
 }
 
 func (lex *Lexer) consumeDot(lval *smiSymType) int {
-	b := lex.next()
-	if bb := lex.peek(); bb == '.' {
-		lex.skip(1)
+	lex.offset++
+	if lex.peek() == '.' {
+		lex.offset++
 		return tDOT_DOT
 	}
-	return int(b)
+	return '.'
 }
 
 func (lex *Lexer) getToken(lval *smiSymType) int {
-	lex.skipWhitespace()
+	lex.skipSpace()
 
 	b := lex.peek()
+	lval.line = lex.lineno
+	var tok int
 	switch {
 	case isLetterByte(b):
-		return lex.consumeIdent(lval)
+		tok = lex.consumeIdent(lval)
 	case isDigitByte(b):
-		return lex.consumeUnsigned(lval)
+		tok = lex.consumeUnsigned(lval)
 	case b == '-':
-		return lex.consumeDash(lval)
+		tok = lex.consumeDash(lval)
 	case b == '\'':
-		return lex.consumeSingleQuote(lval)
+		tok = lex.consumeSingleQuote(lval)
 	case b == '"':
-		return lex.consumeDoubleQuote(lval)
+		tok = lex.consumeDoubleQuote(lval)
 	case b == ':':
-		return lex.consumeColon(lval)
+		tok = lex.consumeColon(lval)
 	case b == '.':
-		return lex.consumeDot(lval)
+		tok = lex.consumeDot(lval)
 	case b != lexEOF:
-		tok := int(lex.next())
-		lex.consumeToken()
-		return tok
+		lex.offset++
+		tok = int(b)
+	default:
+		return lexEOF
 	}
-
-	return lexEOF
+	lex.tokenEnd = lex.offset
+	return tok
 }
 
 func (lex *Lexer) nextState(tok int) {
@@ -508,14 +536,29 @@ func (lex *Lexer) Error(e string) {
 
 // NewLexer creates a new lexer instance
 func NewLexer(r io.Reader) *Lexer {
-	return &Lexer{s: bufio.NewScanner(r)}
+	return &Lexer{r: r, lineno: 1}
 }
 
+// pos returns the line and column of the end of the last token.
 func (lex *Lexer) pos() string {
-	return fmt.Sprintf("%d:%d", lex.lineno, lex.tokenOffset)
+	return fmt.Sprintf("%d:%d", lex.lineno, max(lex.tokenEnd-lex.lineStart, 0))
 }
 
 func setModule(smiLexer *smiLexer, m *Module) {
 	lex := (*smiLexer).(*Lexer)
 	lex.module = m
 }
+
+// addType saves a type definition until the module that contains it is complete.
+func addType(smiLexer *smiLexer, t Type) {
+	lex := (*smiLexer).(*Lexer)
+	lex.types = append(lex.types, t)
+}
+
+// takeTypes returns the saved type definitions for the current module.
+func takeTypes(smiLexer *smiLexer) []Type {
+	lex := (*smiLexer).(*Lexer)
+	types := lex.types
+	lex.types = nil
+	return types
+}
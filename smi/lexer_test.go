@@ -6,8 +6,12 @@
 package smi
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 func TestLexer_Init(t *testing.T) {
@@ -239,3 +243,102 @@ func TestLexer_HexBin(t *testing.T) {
 	}
 
 }
+
+func TestLexer_Reads(t *testing.T) {
+	text := "a OBJECT-TYPE\r\n\"one\r\ntwo\" -- comment\r\n{ b 2147483648 -2147483649 }\r\n"
+	expected := []struct {
+		tok  int
+		id   string
+		text string
+		line int
+	}{
+		{tok: tLOWERCASE_IDENTIFIER, id: "a", line: 1},
+		{tok: tOBJECT_TYPE, id: "OBJECT-TYPE", line: 1},
+		{tok: tQUOTED_STRING, text: "one\ntwo", line: 2},
+		{tok: '{', line: 4},
+		{tok: tLOWERCASE_IDENTIFIER, id: "b", line: 4},
+		{tok: tNUMBER, line: 4},
+		{tok: tNEGATIVENUMBER64, line: 4},
+		{tok: '}', line: 4},
+	}
+	// Reading one byte at a time splits every token across reads
+	lex := NewLexer(iotest.OneByteReader(strings.NewReader(text)))
+	i := 0
+	for {
+		lval := &smiSymType{}
+		tok := lex.Lex(lval)
+		if tok == lexEOF {
+			break
+		}
+		if i == len(expected) {
+			t.Fatalf("unexpected token %d", tok)
+		}
+		e := expected[i]
+		if tok != e.tok || lval.id != e.id || lval.text != e.text || lval.line != e.line {
+			t.Errorf("%d: got %d %q %q line %d, expected %+v", i, tok, lval.id, lval.text, lval.line, e)
+		}
+		i++
+	}
+	if i != len(expected) {
+		t.Errorf("got %d tokens, expected %d", i, len(expected))
+	}
+	if lex.err != nil {
+		t.Fatal(lex.err)
+	}
+}
+
+// readTestdata returns the contents of the MIB files in the testdata directory.
+func readTestdata(b *testing.B) [][]byte {
+	files, err := filepath.Glob(filepath.Join("testdata", "*"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	var texts [][]byte
+	for _, file := range files {
+		text, err := os.ReadFile(file)
+		if err != nil {
+			b.Fatal(err)
+		}
+		texts = append(texts, text)
+	}
+	return texts
+}
+
+func setBytes(b *testing.B, texts [][]byte) {
+	var n int64
+	for _, text := range texts {
+		n += int64(len(text))
+	}
+	b.SetBytes(n)
+	b.ReportAllocs()
+	b.ResetTimer()
+}
+
+func BenchmarkLexer(b *testing.B) {
+	texts := readTestdata(b)
+	setBytes(b, texts)
+	for i := 0; i < b.N; i++ {
+		for _, text := range texts {
+			lex := NewLexer(bytes.NewReader(text))
+			lval := &smiSymType{}
+			for tok := lex.Lex(lval); tok != lexEOF; tok = lex.Lex(lval) {
+			}
+			if lex.err != nil {
+				b.Fatal(lex.err)
+			}
+		}
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	texts := readTestdata(b)
+	setBytes(b, texts)
+	for i := 0; i < b.N; i++ {
+		for _, text := range texts {
+			lex := NewLexer(bytes.NewReader(text))
+			if smiParse(lex) != 0 || lex.err != nil {
+				b.Fatal("parse failed", lex.err)
+			}
+		}
+	}
+}
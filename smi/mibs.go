@@ -9,23 +9,38 @@ package smi
 import (
 	"fmt"
 	"io/ioutil"
-	"log"
 	"os"
 	"path/filepath"
 	"sort"
-	"strconv"
 	"strings"
+	"sync"
 )
 
 // A MIB is a collection of SNMP modules. The MIB provides a high-level
 // API for loading and accessing the contents of parsed MIBs.
+//
+// If the Diagnostics function is set it is called for each Diagnostic
+// reported while loading modules. Otherwise, if the Debug flag is set,
+// the diagnostics are written with the standard logger.
 type MIB struct {
-	Modules   map[string]*Module
-	Root      *Symbol
-	Symbols   map[string]*Symbol
-	Debug     bool
-	dirs      []string
-	loadOrder []string
+	Modules     map[string]*Module
+	Root        *Symbol
+	Symbols     map[string]*Symbol
+	Debug       bool
+	Diagnostics func(Diagnostic)
+	dirs        []string
+	loadOrder   []string
+	aliases     map[string]string
+	usedAliases map[string]string
+
+	definitions      map[string][]*Symbol
+	duplicatePolicy  DuplicatePolicy
+	preferredModules []string
+
+	// textMu guards textIndex, which is built by the first of what may be
+	// concurrent calls to SearchText.
+	textMu    sync.Mutex
+	textIndex *textIndex
 }
 
 type parentRef struct {
@@ -33,7 +48,9 @@ type parentRef struct {
 	Child *Symbol
 }
 
-var replacementModule = map[string]string{
+// defaultModuleAliases maps the names of obsolete modules to the
+// modules that replace them.
+var defaultModuleAliases = map[string]string{
 	"RFC1155-SMI": "SNMPv2-SMI",
 	"RFC-1212":    "SNMPv2-SMI",
 	"RFC1212":     "SNMPv2-SMI",
@@ -46,69 +63,298 @@ var replacementModule = map[string]string{
 // NewMIB creates a MIB object for the modules contained in the dirs directories.
 // Creating a MIB does not load any modules from the directories. You need to call
 // LoadModules() on the resulting MIB object.
+//
+// The MIB is created with aliases that map obsolete SMIv1 module names, such as
+// RFC1213-MIB, to the SMIv2 modules that replace them.
 func NewMIB(dirs ...string) *MIB {
 	mib := &MIB{
 		dirs:    dirs,
 		Modules: make(map[string]*Module),
-		Symbols: make(map[string]*Symbol),
+		aliases: make(map[string]string),
+	}
+	for alias, modName := range defaultModuleAliases {
+		mib.aliases[alias] = modName
+	}
+	mib.resetTree()
+	return mib
+}
+
+// AddModuleAlias makes alias another name for the module modName. The alias is
+// used in place of modName when loading modules, when resolving imports and when
+// looking up names with the OID function. An alias takes precedence over a module
+// with the same name found in the MIB directories. Aliases take effect the next
+// time modules are loaded.
+func (mib *MIB) AddModuleAlias(alias, modName string) {
+	mib.aliases[alias] = modName
+}
+
+// RemoveModuleAlias removes the alias from the MIB.
+func (mib *MIB) RemoveModuleAlias(alias string) {
+	delete(mib.aliases, alias)
+}
+
+// ModuleAliases returns a copy of the aliases defined for the MIB, mapping
+// each alias to the name of the module it stands for.
+func (mib *MIB) ModuleAliases() map[string]string {
+	aliases := make(map[string]string, len(mib.aliases))
+	for alias, modName := range mib.aliases {
+		aliases[alias] = modName
+	}
+	return aliases
+}
+
+// UsedModuleAliases returns the aliases that were used to load the modules
+// currently in the MIB, mapping each alias to the module it stands for.
+func (mib *MIB) UsedModuleAliases() map[string]string {
+	aliases := make(map[string]string, len(mib.usedAliases))
+	for alias, modName := range mib.usedAliases {
+		aliases[alias] = modName
+	}
+	return aliases
+}
+
+// Module returns the module named modName, or the module that modName is
+// an alias for, or nil if the MIB directories have no such module.
+func (mib *MIB) Module(modName string) *Module {
+	return mib.Modules[mib.moduleName(modName)]
+}
+
+// moduleName returns the name of the module that modName is an alias for,
+// or modName itself if it is not an alias.
+func (mib *MIB) moduleName(modName string) string {
+	if newName, ok := mib.aliases[modName]; ok {
+		return newName
 	}
+	return modName
+}
 
+// loadModuleName is like moduleName, but also records the use of an alias
+// while loading modules.
+func (mib *MIB) loadModuleName(modName string) string {
+	newName := mib.moduleName(modName)
+	if newName == modName {
+		return modName
+	}
+	if _, ok := mib.usedAliases[modName]; !ok {
+		mib.report(Diagnostic{Kind: DiagModuleAlias, Module: newName, Related: modName})
+		if mib.usedAliases == nil {
+			mib.usedAliases = make(map[string]string)
+		}
+		mib.usedAliases[modName] = newName
+	}
+	return newName
+}
+
+// resetTree discards all symbols, leaving only the iso root symbol.
+func (mib *MIB) resetTree() {
 	root := Symbol{
-		Name:         "iso",
-		ID:           1,
-		Module:       nil,
-		Parent:       nil,
-		ChildByLabel: make(map[string]*Symbol),
-		ChildByID:    make(map[int]*Symbol),
+		Name:   "iso",
+		ID:     1,
+		Module: nil,
+		Parent: nil,
 	}
 	mib.Root = &root
-	mib.Symbols[root.Name] = &root
-
-	return mib
+	mib.Symbols = map[string]*Symbol{root.Name: &root}
+	mib.definitions = map[string][]*Symbol{root.Name: {&root}}
 }
 
 // LoadModules scans the MIB directories and loads the modules listed by modNames. The imported
 // modules are also loaded. A module's name is the one specified on the first line of the MIB file.
 // The file names do not have to exactly match the module names.
+//
+// Loading is incremental: the symbols of the new modules are added to the existing
+// tree, so Symbol pointers obtained earlier remain valid. Modules that are already
+// loaded are not checked for changes to their files; use Reload or Watch for that.
+// If a module cannot be found or parsed, none of the listed modules are loaded.
 func (mib *MIB) LoadModules(modNames ...string) error {
-	err := mib.scanDirs()
+	scanMods, err := mib.scanDirs()
 	if err != nil {
 		return err
 	}
+	for modName, mod := range scanMods {
+		if old := mib.Modules[modName]; old == nil || !old.IsLoaded {
+			mib.Modules[modName] = mod
+		}
+	}
 
 	// Load all modules if no names are provided
 	if len(modNames) == 0 {
 		modNames = make([]string, 0, len(mib.Modules))
-		for name := range mib.Modules {
-			modNames = append(modNames, name)
+		for modName := range mib.Modules {
+			modNames = append(modNames, modName)
 		}
-		sort.SliceStable(modNames, func(i, j int) bool {
-			return modNames[i] < modNames[j]
-		})
+		sort.Strings(modNames)
 	}
 
+	start := len(mib.loadOrder)
 	for _, modName := range modNames {
 		err := mib.loadModule(modName)
 		if err != nil {
+			mib.abandonLoad(start)
 			return err
 		}
 	}
-	return mib.indexModules()
+	mib.textIndex = nil
+	return mib.indexModules(start)
 }
 
-func (mib *MIB) addSymbol(sym *Symbol) bool {
-	if oldSym, ok := mib.Symbols[sym.Name]; ok {
-		if mib.Debug {
-			log.Printf("imported symbol %v duplicates name of %v, ignoring", sym, oldSym)
+// abandonLoad marks the modules loaded since the load order had the length
+// start as not loaded again. Their symbols have not been indexed yet.
+func (mib *MIB) abandonLoad(start int) {
+	loaded := make(map[string]bool, start)
+	for _, modName := range mib.loadOrder[:start] {
+		loaded[modName] = true
+	}
+	for modName, mod := range mib.Modules {
+		if mod.IsLoaded && !loaded[modName] {
+			mod.IsLoaded = false
+			mod.Symbols = nil
 		}
-		return false
 	}
-	mib.Symbols[sym.Name] = sym
-	return true
+	mib.loadOrder = mib.loadOrder[:start]
+}
+
+// UnloadModules removes the modules listed by modNames and their symbols from the MIB.
+// Symbols of other modules that were hidden because they duplicate the name of a
+// removed symbol become visible again. An error is returned, and nothing is unloaded,
+// if other loaded modules import from the listed modules.
+func (mib *MIB) UnloadModules(modNames ...string) (*Changes, error) {
+	return mib.unloadModules(modNames, false)
 }
 
-func (mib *MIB) indexModules() error {
+// UnloadModulesCascade is like UnloadModules, but also unloads the modules that
+// directly or indirectly import from the listed modules.
+func (mib *MIB) UnloadModulesCascade(modNames ...string) (*Changes, error) {
+	return mib.unloadModules(modNames, true)
+}
+
+func (mib *MIB) unloadModules(modNames []string, cascade bool) (*Changes, error) {
+	unload := make(map[string]bool)
+	for _, modName := range modNames {
+		modName = mib.moduleName(modName)
+		mod := mib.Modules[modName]
+		if mod == nil || !mod.IsLoaded {
+			return nil, fmt.Errorf("unloading: module not loaded: %s", modName)
+		}
+		unload[modName] = true
+	}
+
+	for found := true; found; {
+		found = false
+		for _, modName := range mib.loadOrder {
+			if unload[modName] {
+				continue
+			}
+			for _, impName := range mib.moduleImports(mib.Modules[modName]) {
+				if !unload[impName] {
+					continue
+				}
+				if !cascade {
+					return nil, fmt.Errorf("unloading: module %s is imported by %s", impName, modName)
+				}
+				unload[modName] = true
+				found = true
+				break
+			}
+		}
+	}
+
+	changes := &Changes{}
+	modules := make(map[string]*Module, len(mib.Modules))
+	for modName, mod := range mib.Modules {
+		modules[modName] = mod
+	}
+	var loadNames []string
 	for _, modName := range mib.loadOrder {
+		if unload[modName] {
+			changes.RemovedModules = append(changes.RemovedModules, modName)
+		} else {
+			loadNames = append(loadNames, modName)
+		}
+	}
+	err := mib.rebuild(modules, loadNames, changes)
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// moduleImports returns the names of the modules that mod imports symbols from.
+func (mib *MIB) moduleImports(mod *Module) []string {
+	var names []string
+	for _, imp := range mod.Imports {
+		if len(imp.Symbols) == 0 {
+			continue
+		}
+		names = append(names, mib.moduleName(imp.From))
+	}
+	return names
+}
+
+func (mib *MIB) addSymbol(sym *Symbol, line int) {
+	if oldSym, ok := mib.Symbols[sym.Name]; ok {
+		mib.report(Diagnostic{
+			Kind:    DiagDuplicateSymbol,
+			Module:  sym.Module.Name,
+			File:    sym.Module.File,
+			Line:    line,
+			Symbol:  sym.Name,
+			Related: oldSym.String(),
+		})
+	} else {
+		mib.Symbols[sym.Name] = sym
+	}
+	mib.definitions[sym.Name] = append(mib.definitions[sym.Name], sym)
+}
+
+// attachSymbol adds sym to the children of parent. If another symbol already
+// has the same OID, the two symbols share their children so that descendants
+// defined relative to either of them can be found in the tree. A named symbol
+// replaces an anonymous node in the tree.
+//
+// The child maps of a symbol are only allocated once it has children, so that
+// the many leaf symbols of a tree do not pay for them.
+func attachSymbol(parent, sym *Symbol) {
+	sym.Parent = parent
+	if sym.Name != "" {
+		if parent.ChildByLabel == nil {
+			parent.ChildByLabel = make(map[string]*Symbol)
+		}
+		parent.ChildByLabel[sym.Name] = sym
+	}
+	if parent.ChildByID == nil {
+		parent.ChildByID = make(map[int]*Symbol)
+	}
+	existing := parent.ChildByID[sym.ID]
+	if existing == nil {
+		parent.ChildByID[sym.ID] = sym
+		return
+	}
+	// The maps must exist before they are shared, so that children added
+	// later through either symbol are seen by both.
+	if existing.ChildByID == nil {
+		existing.ChildByID = make(map[int]*Symbol)
+	}
+	if existing.ChildByLabel == nil {
+		existing.ChildByLabel = make(map[string]*Symbol)
+	}
+	for id, child := range sym.ChildByID {
+		existing.ChildByID[id] = child
+	}
+	for label, child := range sym.ChildByLabel {
+		existing.ChildByLabel[label] = child
+	}
+	sym.ChildByID = existing.ChildByID
+	sym.ChildByLabel = existing.ChildByLabel
+	if existing.Name == "" && sym.Name != "" {
+		parent.ChildByID[sym.ID] = sym
+	}
+}
+
+// indexModules adds the symbols of the modules in the load order from the
+// position start onwards to the tree.
+func (mib *MIB) indexModules(start int) error {
+	for _, modName := range mib.loadOrder[start:] {
 		mod, ok := mib.Modules[modName]
 		if !ok {
 			return fmt.Errorf("indexing: module not found: %s", modName)
@@ -118,7 +364,8 @@ func (mib *MIB) indexModules() error {
 		}
 
 		var unresolved []parentRef
-		for _, n := range mod.Nodes {
+		for j := range mod.Nodes {
+			n := &mod.Nodes[j]
 			if len(n.IDs) < 2 {
 				return fmt.Errorf("%s: unknown IDs format: %v", modName, n.IDs)
 			}
@@ -138,30 +385,37 @@ func (mib *MIB) indexModules() error {
 					return fmt.Errorf("%s: expected numeric index: %v", modName, n.IDs)
 				}
 				var label string
+				var nodeType NodeType
+				var node *Node
 				if i < len(n.IDs)-1 {
+					// Reuse the node of an earlier definition below the
+					// same parent instead of creating another anonymous one
+					if child := parent.child(id); child != nil {
+						parent = child
+						continue
+					}
 					label = ""
 				} else {
 					label = n.Label
+					nodeType = n.Type
+					node = n
 				}
 				sym := &Symbol{
-					Name:         label,
-					ID:           id,
-					Module:       mod,
-					Parent:       parent,
-					ChildByLabel: make(map[string]*Symbol),
-					ChildByID:    make(map[int]*Symbol),
+					Name:   label,
+					ID:     id,
+					Type:   nodeType,
+					Module: mod,
+					Node:   node,
+					Parent: parent,
 				}
 				if sym.Name != "" {
 					mod.Symbols[sym.Name] = sym
-					if !mib.addSymbol(sym) {
-						continue
-					}
+					mib.addSymbol(sym, n.Line)
 				}
 				if parent == nil {
 					unresolved = append(unresolved, parentRef{Label: parentLabel, Child: sym})
 				} else {
-					parent.ChildByLabel[sym.Name] = sym
-					parent.ChildByID[sym.ID] = sym
+					attachSymbol(parent, sym)
 				}
 				parent = sym
 			}
@@ -173,13 +427,28 @@ func (mib *MIB) indexModules() error {
 			if parent == nil {
 				return fmt.Errorf("%s: cannot resolve symbol %v, parent of %s", modName, ref.Label, sym.Name)
 			}
-			parent.ChildByLabel[sym.Name] = sym
-			parent.ChildByID[sym.ID] = sym
+			attachSymbol(parent, sym)
 		}
 	}
+	mib.resolveDuplicates()
+	sortChildren(mib.Root)
 	return nil
 }
 
+// sortChildren records the sorted IDs of the children of each symbol in the tree.
+func sortChildren(sym *Symbol) {
+	if len(sym.ChildByID) == 0 {
+		sym.childIDs = nil
+		return
+	}
+	sym.childIDs = make([]int, 0, len(sym.ChildByID))
+	for id, child := range sym.ChildByID {
+		sym.childIDs = append(sym.childIDs, id)
+		sortChildren(child)
+	}
+	sort.Ints(sym.childIDs)
+}
+
 func (mib *MIB) findSymbol(mod *Module, label string) *Symbol {
 	if sym, ok := mod.Symbols[label]; ok {
 		return sym
@@ -187,16 +456,16 @@ func (mib *MIB) findSymbol(mod *Module, label string) *Symbol {
 	for _, imp := range mod.Imports {
 		for _, impLabel := range imp.Symbols {
 			if label == impLabel {
-				importName := imp.From
-				if newName, ok := replacementModule[importName]; ok {
-					importName = newName
-				}
-
+				importName := mib.loadModuleName(imp.From)
 				impMod := mib.Modules[importName]
 				if impMod == nil {
-					if mib.Debug {
-						log.Printf("imported module not found: %s", imp.From)
-					}
+					mib.report(Diagnostic{
+						Kind:    DiagMissingImport,
+						Module:  mod.Name,
+						File:    mod.File,
+						Line:    imp.Line,
+						Related: imp.From,
+					})
 					return nil
 				}
 				return mib.findSymbol(impMod, label)
@@ -210,9 +479,7 @@ func (mib *MIB) findSymbol(mod *Module, label string) *Symbol {
 }
 
 func (mib *MIB) loadModule(modName string) error {
-	if newName, ok := replacementModule[modName]; ok {
-		modName = newName
-	}
+	modName = mib.loadModuleName(modName)
 	mod := mib.Modules[modName]
 	if mod == nil {
 		return fmt.Errorf("loading: module not found: %s", modName)
@@ -220,18 +487,22 @@ func (mib *MIB) loadModule(modName string) error {
 	if mod.IsLoaded {
 		return nil
 	}
-	parsedMod, err := ParseModule(mod.File)
-	if err != nil {
-		return err
-	}
-	if mod.Name != parsedMod.Name {
-		return fmt.Errorf("found module %s in file %s, expected %s", parsedMod.Name, mod.File, mod.Name)
+	if !mod.parsed {
+		parsedMod, err := ParseModule(mod.File)
+		if err != nil {
+			return err
+		}
+		if mod.Name != parsedMod.Name {
+			return fmt.Errorf("found module %s in file %s, expected %s", parsedMod.Name, mod.File, mod.Name)
+		}
+		mod.Nodes = parsedMod.Nodes
+		mod.Types = parsedMod.Types
+		mod.Imports = parsedMod.Imports
+		mod.parsed = true
 	}
-	mod.Nodes = parsedMod.Nodes
-	mod.Imports = parsedMod.Imports
 	mod.IsLoaded = true
 	mod.Symbols = make(map[string]*Symbol)
-	err = mib.loadImports(mod.Imports)
+	err := mib.loadImports(mod.Imports)
 	if err != nil {
 		return fmt.Errorf("loading imports for %s: %v", modName, err)
 	}
@@ -255,44 +526,22 @@ func (mib *MIB) loadImports(imports []Import) error {
 	return nil
 }
 
-func (mib *MIB) scanDirs() error {
+func (mib *MIB) scanDirs() (map[string]*Module, error) {
 	scanMods := make(map[string]*Module)
 	for _, dirname := range mib.dirs {
 		if fi, err := os.Stat(dirname); !os.IsNotExist(err) {
 			if fi.IsDir() {
-				err = scanDir(dirname, &scanMods)
+				err = mib.scanDir(dirname, &scanMods)
 				if err != nil {
-					return err
+					return nil, err
 				}
 			}
 		}
 	}
-
-	// Update modules that exist in MIB
-	for modName, mod := range mib.Modules {
-		if newMod, ok := scanMods[modName]; ok {
-			// Module already exists in MIB
-			if mod.File != newMod.File {
-				if mib.Debug {
-					log.Printf("module %s: replacing %s with %s", modName, mod.File, newMod.File)
-				}
-				mib.Modules[modName] = newMod
-			}
-			delete(scanMods, modName)
-		} else {
-			// Module has been removed
-			delete(mib.Modules, modName)
-		}
-	}
-
-	// Add modules that are new in MIB
-	for modName, mod := range scanMods {
-		mib.Modules[modName] = mod
-	}
-	return nil
+	return scanMods, nil
 }
 
-func scanDir(dirname string, scanMods *map[string]*Module) error {
+func (mib *MIB) scanDir(dirname string, scanMods *map[string]*Module) error {
 	files, err := ioutil.ReadDir(dirname)
 	if err != nil {
 		return err
@@ -306,11 +555,17 @@ func scanDir(dirname string, scanMods *map[string]*Module) error {
 			continue
 		}
 		if moduleName, err := ModuleName(absPath); err == nil {
-			(*scanMods)[moduleName] = &Module{Name: moduleName, File: absPath}
+			(*scanMods)[moduleName] = &Module{
+				Name:    moduleName,
+				File:    absPath,
+				modTime: fi.ModTime(),
+				size:    fi.Size(),
+			}
 		} else {
 			if _, ok := err.(NotAModuleError); !ok {
 				return err
 			}
+			mib.report(Diagnostic{Kind: DiagNotAModule, File: absPath})
 		}
 	}
 	return nil
@@ -337,79 +592,106 @@ func (mib *MIB) Symbol(oid OID) (*Symbol, OID) {
 }
 
 // SymbolString returns a string representation of the information
-// provided by the Symbol function.
+// provided by the Symbol function. Use FormatOID for other formats.
 func (mib *MIB) SymbolString(oid OID) string {
-	if len(oid) == 0 {
-		return ""
-	}
-	sym, idx := mib.Symbol(oid)
-	if sym == nil {
-		return oid.String()
-	}
-	if len(idx) == 0 {
-		return sym.String()
-	}
-	return sym.String() + "." + idx.String()
+	return mib.FormatOID(oid, FormatOptions{})
 }
 
-// OID parses the name string in the format provided by the
-// SymbolString function (e.g. Module::Symbol.1.2.3) and returns
-// an OID object. The module and index parts of the string are
-// optional.
+// OID parses a name in one of the formats produced by FormatOID and returns
+// the OID it identifies. The name is a path of labels and numbers separated by
+// dots, optionally preceded by a dot or by a module name and "::", followed by
+// an index. The first label may be any symbol in the MIB or, if a module is
+// given, any symbol defined by the module; each following label must name a
+// child of the previous symbol. The module may be a module alias. For example:
+//
+//	IF-MIB::ifDescr.3
+//	.iso.org.dod.internet.mgmt.mib-2.system.sysDescr.0
+//	1.3.6.internet.2
+//	ipAddrEntry.ipAdEntAddr.10.0.0.1
+//	vacmGroupName.3."public"
+//	vacmGroupName[3]["public"]
+//
+// An index given as numbers is used as is. Otherwise the symbol must be a
+// table column and the index values are encoded according to the types of
+// the objects in the INDEX clause of the table, as described for FormatOptions.
 func (mib *MIB) OID(name string) (OID, error) {
 	var modulePart string
-	var namePart string
-	var indexPart string
-
 	if i := strings.Index(name, "::"); i != -1 {
 		modulePart = name[:i]
 		name = name[i+2:]
 	}
-	if i := strings.IndexByte(name, '.'); i != -1 {
-		namePart = name[:i]
-		indexPart = name[i+1:]
-	} else {
-		namePart = name
-		indexPart = ""
+	path, brackets, err := splitOIDName(name)
+	if err != nil {
+		return nil, err
 	}
-	if namePart == "" {
+	if len(path) == 0 {
 		return nil, fmt.Errorf("missing OID name")
 	}
 
 	var sym *Symbol
-	if modulePart == "" {
-		sym = mib.Symbols[namePart]
-		if sym == nil {
-			indexPart = name
-			namePart = ""
-		}
-	} else {
-		mod := mib.Modules[modulePart]
+	var oid OID
+	switch {
+	case modulePart != "":
+		mod := mib.Modules[mib.moduleName(modulePart)]
 		if mod == nil {
 			return nil, fmt.Errorf("module %s not in MIB", modulePart)
 		}
-		sym = mod.Symbols[namePart]
+		sym = mod.Symbols[path[0]]
 		if sym == nil {
-			return nil, fmt.Errorf("name %s not in module", namePart)
+			return nil, fmt.Errorf("name %s not in module", path[0])
+		}
+	case !isNumber(path[0]):
+		sym = mib.Symbols[path[0]]
+		if sym == nil && len(mib.definitions[path[0]]) > 0 {
+			return nil, fmt.Errorf("name %s is ambiguous, defined in modules %s", path[0],
+				strings.Join(mib.DefiningModules(path[0]), ", "))
+		}
+		if sym == nil {
+			return nil, fmt.Errorf("unknown name %s", path[0])
 		}
 	}
-
-	var idx OID
-	if indexPart != "" {
-		idxParts := strings.Split(indexPart, ".")
-		for _, part := range idxParts {
-			n, err := strconv.Atoi(part)
-			if err != nil {
-				return nil, fmt.Errorf("invalid index number %s: %v", part, err)
+	if sym != nil {
+		oid = mib.symbolOID(sym)
+		path = path[1:]
+	}
+
+	// Follow the path down the tree for as long as it names symbols. The
+	// rest of the path is the index.
+	index := path
+	for i, part := range path {
+		var child *Symbol
+		if n, err := parseSubID(part); err == nil {
+			if sym == nil && len(oid) == 0 && n == mib.Root.ID {
+				child = mib.Root
+			} else if sym != nil {
+				child = sym.ChildByID[n]
 			}
-			idx = append(idx, n)
+		} else if sym != nil {
+			child = sym.ChildByLabel[part]
 		}
+		if child == nil {
+			index = path[i:]
+			break
+		}
+		sym = child
+		oid = append(oid, child.ID)
+		index = nil
 	}
 
-	if sym == nil {
-		return idx, nil
+	var idx OID
+	if len(brackets) == 0 {
+		idx, err = parseNumericIndex(index)
+		if err != nil {
+			idx, err = mib.encodeIndex(sym, index, false)
+		}
+	} else if len(index) == 0 {
+		idx, err = mib.encodeIndex(sym, brackets, true)
+	} else {
+		err = fmt.Errorf("index of %s mixes dotted and bracketed values", name)
+	}
+	if err != nil {
+		return nil, err
 	}
-	oid := mib.symbolOID(sym)
 	return append(oid, idx...), nil
 }
 
@@ -424,22 +706,8 @@ func (mib *MIB) symbolOID(sym *Symbol) OID {
 // VisitSymbols walks all symbols defined in the MIB in order by OID. The action function
 // is called once for each symbol.
 func (mib *MIB) VisitSymbols(action func(sym *Symbol, oid OID)) {
-	sym := mib.Root
-	oid := OID{sym.ID}
-	visitChildSymbols(sym, oid, action)
-}
-
-func visitChildSymbols(sym *Symbol, oid OID, action func(sym *Symbol, oid OID)) {
-	var keys []int
-	for k := range sym.ChildByID {
-		keys = append(keys, k)
-	}
-	sort.Ints(keys)
-	for _, k := range keys {
-		childSym := sym.ChildByID[k]
-		childOID := append(OID{}, oid...)
-		childOID = append(childOID, childSym.ID)
-		action(childSym, childOID)
-		visitChildSymbols(childSym, childOID, action)
-	}
+	walkChildren(mib.Root, OID{mib.Root.ID}, func(sym *Symbol, oid OID) WalkAction {
+		action(sym, oid)
+		return WalkContinue
+	})
 }
@@ -7,8 +7,10 @@ package smi
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // NodeType distinguishes the different types of objects that make
@@ -24,6 +26,21 @@ const (
 	NodeNotification
 )
 
+var nodeTypeNames = map[NodeType]string{
+	NodeNotSupported: "not supported",
+	NodeModuleID:     "MODULE-IDENTITY",
+	NodeObjectID:     "OBJECT IDENTIFIER",
+	NodeObjectType:   "OBJECT-TYPE",
+	NodeNotification: "NOTIFICATION-TYPE",
+}
+
+func (t NodeType) String() string {
+	if name, ok := nodeTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("NodeType(%d)", int(t))
+}
+
 // SubID is a label and/or ID associated with a Node
 type SubID struct {
 	ID    int
@@ -45,13 +62,27 @@ func (s SubID) String() string {
 type Import struct {
 	From    string
 	Symbols []string
+	Line    int
 }
 
-// A Node represents a parse node in an SMI document
+// A Node represents a parse node in an SMI document. The Syntax, Units,
+// Access, Index and Augments fields are only set for OBJECT-TYPE nodes and
+// the Objects field for NOTIFICATION-TYPE nodes. Fields for clauses missing
+// from the definition are empty.
 type Node struct {
-	Label string
-	Type  NodeType
-	IDs   []SubID
+	Label       string
+	Type        NodeType
+	IDs         []SubID
+	Line        int
+	Syntax      *Syntax
+	Units       string
+	Access      string
+	Status      string
+	Description string
+	Reference   string
+	Index       []IndexObject
+	Augments    string
+	Objects     []string
 }
 
 // A Module contains all of the parse results for a single module file.
@@ -61,21 +92,29 @@ type Module struct {
 	File     string
 	Imports  []Import
 	Nodes    []Node
+	Types    []Type
 	IsLoaded bool
 	Symbols  map[string]*Symbol
+	parsed   bool
+	modTime  time.Time
+	size     int64
 }
 
 // A Symbol represents a single symbol in the tree of identifiers.
 // The tree can be traversed by label or by ID. The collection of
 // IDs in the path from the root of the tree to the symbol is the
-// object identifier (OID) of the symbol.
+// object identifier (OID) of the symbol. Node is the parse node that
+// defines the symbol, or nil if the symbol has no name.
 type Symbol struct {
 	Name         string
 	ID           int
+	Type         NodeType
 	Module       *Module
+	Node         *Node
 	Parent       *Symbol
 	ChildByLabel map[string]*Symbol
 	ChildByID    map[int]*Symbol
+	childIDs     []int
 }
 
 func (s *Symbol) String() string {
@@ -85,9 +124,86 @@ func (s *Symbol) String() string {
 	return s.Module.Name + "::" + s.Name
 }
 
+// IsScalar returns true if the symbol is an OBJECT-TYPE that is not part of a table.
+func (s *Symbol) IsScalar() bool {
+	return s.Type == NodeObjectType && len(s.ChildByID) == 0 && !s.Parent.isObjectType()
+}
+
+// IsTable returns true if the symbol is an OBJECT-TYPE defining a table.
+func (s *Symbol) IsTable() bool {
+	return s.Type == NodeObjectType && len(s.ChildByID) > 0 && !s.Parent.isObjectType()
+}
+
+// IsRow returns true if the symbol is an OBJECT-TYPE defining a table row.
+func (s *Symbol) IsRow() bool {
+	return s.Type == NodeObjectType && len(s.ChildByID) > 0 && s.Parent.isObjectType()
+}
+
+// IsColumn returns true if the symbol is an OBJECT-TYPE defining a table column.
+func (s *Symbol) IsColumn() bool {
+	return s.Type == NodeObjectType && len(s.ChildByID) == 0 && s.Parent.isObjectType()
+}
+
+func (s *Symbol) isObjectType() bool {
+	return s != nil && s.Type == NodeObjectType
+}
+
+// child returns the child of s with the given ID. It returns nil if there
+// is none or if s is nil, as it is for a symbol whose parent is unresolved.
+func (s *Symbol) child(id int) *Symbol {
+	if s == nil {
+		return nil
+	}
+	return s.ChildByID[id]
+}
+
+// Limits on the size of object identifiers defined by RFC 2578.
+const (
+	MaxOIDLen = 128       // maximum number of sub-identifiers
+	MaxSubID  = 1<<32 - 1 // maximum value of a sub-identifier
+)
+
 // The OID type represents a dot-formated MIB object ID
 type OID []int
 
+// ParseOID parses a dot-formatted object ID such as 1.3.6.1.2.1 or .1.3.6.1.2.1.
+// The sub-identifiers must be numbers, and the result must satisfy the limits
+// checked by Validate. On 32-bit platforms sub-identifiers above math.MaxInt
+// are rejected, since they cannot be held in an OID.
+func ParseOID(s string) (OID, error) {
+	str := strings.TrimPrefix(s, ".")
+	if str == "" {
+		return nil, fmt.Errorf("empty OID: %q", s)
+	}
+	parts := strings.Split(str, ".")
+	oid := make(OID, len(parts))
+	for i, part := range parts {
+		n, err := parseSubID(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sub-identifier %q in OID %q", part, s)
+		}
+		oid[i] = n
+	}
+	if err := oid.Validate(); err != nil {
+		return nil, err
+	}
+	return oid, nil
+}
+
+// parseSubID converts a sub-identifier written as a decimal number. Numbers
+// above MaxSubID are rejected, as are numbers that do not fit in an int on
+// 32-bit platforms.
+func parseSubID(s string) (int, error) {
+	n, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	if n > math.MaxInt {
+		return 0, fmt.Errorf("sub-identifier %s does not fit in an int", s)
+	}
+	return int(n), nil
+}
+
 func (oid OID) String() string {
 	parts := make([]string, len(oid))
 	for i, n := range oid {
@@ -96,6 +212,23 @@ func (oid OID) String() string {
 	return strings.Join(parts, ".")
 }
 
+// Validate returns an error if the object ID is empty, has more than MaxOIDLen
+// sub-identifiers or has a sub-identifier outside the range 0 to MaxSubID.
+func (oid OID) Validate() error {
+	if len(oid) == 0 {
+		return fmt.Errorf("empty OID")
+	}
+	if len(oid) > MaxOIDLen {
+		return fmt.Errorf("OID has %d sub-identifiers, the maximum is %d", len(oid), MaxOIDLen)
+	}
+	for i, n := range oid {
+		if n < 0 || int64(n) > MaxSubID {
+			return fmt.Errorf("sub-identifier %d of OID %s out of range: %d", i+1, oid, n)
+		}
+	}
+	return nil
+}
+
 // Equal returns true if the two object IDs have the same value
 func (oid OID) Equal(other OID) bool {
 	if len(oid) != len(other) {
@@ -108,3 +241,53 @@ func (oid OID) Equal(other OID) bool {
 	}
 	return true
 }
+
+// Compare compares two object IDs in lexicographic order, which is the order
+// used by SNMP GETNEXT requests. The result is -1 if oid comes before other,
+// 0 if they are equal and +1 if oid comes after other.
+func (oid OID) Compare(other OID) int {
+	for i := 0; i < len(oid) && i < len(other); i++ {
+		if oid[i] < other[i] {
+			return -1
+		}
+		if oid[i] > other[i] {
+			return 1
+		}
+	}
+	switch {
+	case len(oid) < len(other):
+		return -1
+	case len(oid) > len(other):
+		return 1
+	}
+	return 0
+}
+
+// HasPrefix returns true if oid begins with the sub-identifiers of prefix.
+// Every object ID has itself as a prefix.
+func (oid OID) HasPrefix(prefix OID) bool {
+	return len(oid) >= len(prefix) && oid[:len(prefix)].Equal(prefix)
+}
+
+// IsDescendantOf returns true if oid is in the subtree below ancestor.
+// Unlike HasPrefix, an object ID is not a descendant of itself.
+func (oid OID) IsDescendantOf(ancestor OID) bool {
+	return len(oid) > len(ancestor) && oid.HasPrefix(ancestor)
+}
+
+// Parent returns the object ID with the last sub-identifier removed, or
+// nil if the object ID is empty.
+func (oid OID) Parent() OID {
+	if len(oid) == 0 {
+		return nil
+	}
+	return append(OID{}, oid[:len(oid)-1]...)
+}
+
+// Append returns a new object ID consisting of oid followed by ids.
+// The receiver is not modified.
+func (oid OID) Append(ids ...int) OID {
+	result := make(OID, 0, len(oid)+len(ids))
+	result = append(result, oid...)
+	return append(result, ids...)
+}
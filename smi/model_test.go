@@ -1,6 +1,10 @@
 package smi
 
-import "testing"
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
 
 func TestOIDString(t *testing.T) {
 	tests := []struct {
@@ -20,3 +24,177 @@ func TestOIDString(t *testing.T) {
 	}
 }
 
+func TestParseOID(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected OID
+	}{
+		{"1.3.6.1", OID{1, 3, 6, 1}},
+		{".1.3.6.1", OID{1, 3, 6, 1}},
+		{"0", OID{0}},
+		{"", nil},
+		{".", nil},
+		{"..1", nil},
+		{"1.3.", nil},
+		{"1..3", nil},
+		{"1.-3", nil},
+		{"1.3a", nil},
+		{"1.4294967296", nil},
+		{"1 .3", nil},
+	}
+	for _, test := range tests {
+		result, err := ParseOID(test.in)
+		if err != nil && test.expected != nil {
+			t.Errorf("%q: %v", test.in, err)
+		}
+		if err == nil && test.expected == nil {
+			t.Errorf("%q: expected error, got %s", test.in, result)
+		}
+		if !result.Equal(test.expected) {
+			t.Errorf("%q: got %s, expected %s", test.in, result, test.expected)
+		}
+	}
+
+	long := strings.Repeat("1.", MaxOIDLen) + "1"
+	if _, err := ParseOID(long); err == nil {
+		t.Errorf("expected error parsing OID with %d sub-identifiers", MaxOIDLen+1)
+	}
+	long = strings.Repeat("1.", MaxOIDLen-1) + "1"
+	if _, err := ParseOID(long); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestOIDValidate(t *testing.T) {
+	tests := []struct {
+		oid   OID
+		valid bool
+	}{
+		{OID{1, 3, 6}, true},
+		{OID{0, 0}, true},
+		{OID{}, false},
+		{nil, false},
+		{OID{1, -1}, false},
+		{make(OID, MaxOIDLen), true},
+		{make(OID, MaxOIDLen+1), false},
+	}
+	for _, test := range tests {
+		err := test.oid.Validate()
+		if (err == nil) != test.valid {
+			t.Errorf("%s: expected valid %v, got %v", test.oid, test.valid, err)
+		}
+	}
+}
+
+func TestOIDMaxSubID(t *testing.T) {
+	if strconv.IntSize < 64 {
+		// MaxSubID does not fit in an int
+		if oid, err := ParseOID("1.4294967295"); err == nil {
+			t.Errorf("expected error, got %s", oid)
+		}
+		if oid, _, err := DecodeOID([]byte{0x06, 0x06, 0x2b, 0x8f, 0xff, 0xff, 0xff, 0x7f}); err == nil {
+			t.Errorf("expected error, got %s", oid)
+		}
+		return
+	}
+
+	var maxSubID int64 = MaxSubID
+	max := int(maxSubID)
+	oid, err := ParseOID("1.4294967295")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !oid.Equal(OID{1, max}) {
+		t.Errorf("got %s", oid)
+	}
+	if err := (OID{1, max}).Validate(); err != nil {
+		t.Error(err)
+	}
+	if err := (OID{1, max + 1}).Validate(); err == nil {
+		t.Errorf("expected error for sub-identifier %d", max+1)
+	}
+	if result := (OID{1, 3, max}).Compare(OID{1, 4}); result != -1 {
+		t.Errorf("got %d, expected -1", result)
+	}
+	oid, _, err = DecodeOID([]byte{0x06, 0x06, 0x2b, 0x8f, 0xff, 0xff, 0xff, 0x7f})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !oid.Equal(OID{1, 3, max}) {
+		t.Errorf("got %s", oid)
+	}
+}
+
+func TestOIDCompare(t *testing.T) {
+	tests := []struct {
+		a, b     OID
+		expected int
+	}{
+		{OID{}, OID{}, 0},
+		{OID{}, OID{1}, -1},
+		{OID{1}, OID{}, 1},
+		{OID{1, 3, 6}, OID{1, 3, 6}, 0},
+		{OID{1, 3, 6}, OID{1, 3, 6, 1}, -1},
+		{OID{1, 3, 6, 1}, OID{1, 3, 6}, 1},
+		{OID{1, 3, 6, 2}, OID{1, 3, 7}, -1},
+		{OID{1, 3, 10}, OID{1, 3, 9, 1}, 1},
+	}
+	for _, test := range tests {
+		result := test.a.Compare(test.b)
+		if result != test.expected {
+			t.Errorf("%s <=> %s: got %d, expected %d", test.a, test.b, result, test.expected)
+		}
+	}
+}
+
+func TestOIDPrefix(t *testing.T) {
+	tests := []struct {
+		oid, prefix  OID
+		hasPrefix    bool
+		isDescendant bool
+	}{
+		{OID{1, 3, 6, 1}, OID{1, 3}, true, true},
+		{OID{1, 3, 6, 1}, OID{1, 3, 6, 1}, true, false},
+		{OID{1, 3, 6, 1}, OID{}, true, true},
+		{OID{}, OID{}, true, false},
+		{OID{1, 3}, OID{1, 3, 6}, false, false},
+		{OID{1, 3, 6, 1}, OID{1, 3, 7}, false, false},
+		{OID{1, 30}, OID{1, 3}, false, false},
+	}
+	for _, test := range tests {
+		if result := test.oid.HasPrefix(test.prefix); result != test.hasPrefix {
+			t.Errorf("%s.HasPrefix(%s): got %v", test.oid, test.prefix, result)
+		}
+		if result := test.oid.IsDescendantOf(test.prefix); result != test.isDescendant {
+			t.Errorf("%s.IsDescendantOf(%s): got %v", test.oid, test.prefix, result)
+		}
+	}
+}
+
+func TestOIDParentAppend(t *testing.T) {
+	oid := OID{1, 3, 6, 1}
+	parent := oid.Parent()
+	if !parent.Equal(OID{1, 3, 6}) {
+		t.Errorf("parent: got %s", parent)
+	}
+	if p := (OID{1}).Parent(); p == nil || len(p) != 0 {
+		t.Error("expected empty parent of single sub-identifier OID")
+	}
+	if p := (OID{}).Parent(); p != nil {
+		t.Error("expected nil parent of empty OID")
+	}
+
+	// Appending to the parent must not overwrite the original OID
+	child := parent.Append(9, 10)
+	if !child.Equal(OID{1, 3, 6, 9, 10}) {
+		t.Errorf("append: got %s", child)
+	}
+	if !oid.Equal(OID{1, 3, 6, 1}) {
+		t.Errorf("append modified original: %s", oid)
+	}
+	sub := oid[:2]
+	sub.Append(7)
+	if !oid.Equal(OID{1, 3, 6, 1}) {
+		t.Errorf("append modified shared array: %s", oid)
+	}
+}
@@ -8,7 +8,6 @@
 package smi
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 	"os"
@@ -28,8 +27,7 @@ func ParseModule(filename string) (*Module, error) {
 		panic(err)
 	}
 	defer mustClose(file)
-	reader := bufio.NewReader(file)
-	lex := NewLexer(reader)
+	lex := NewLexer(file)
 	ret := smiParse(lex)
 	if lex.err != nil {
 		return nil, fmt.Errorf("%s:%v", filename, lex.err)
@@ -46,7 +44,7 @@ func ParseModule(filename string) (*Module, error) {
 type NotAModuleError string
 
 func (f NotAModuleError) Error() string {
-	return fmt.Sprintf("not a module file: %s", f)
+	return fmt.Sprintf("not a module file: %s", string(f))
 }
 
 // Filename returns the name of the file that is not a valid module.
@@ -62,8 +60,7 @@ func ModuleName(filename string) (string, error) {
 		return "", err
 	}
 	defer mustClose(file)
-	r := bufio.NewReader(file)
-	lex := NewLexer(r)
+	lex := NewLexer(file)
 	lval := smiSymType{}
 	tok := lex.Lex(&lval)
 	if tok == tUPPERCASE_IDENTIFIER {
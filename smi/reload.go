@@ -0,0 +1,225 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// A SymbolChange describes a symbol that was added, removed or moved
+// when modules were reloaded or unloaded. The name is in the Module::name format.
+// OldOID is nil for added symbols and NewOID is nil for removed symbols.
+type SymbolChange struct {
+	Name   string
+	OldOID OID
+	NewOID OID
+}
+
+// Changes describes the differences in the MIB after modules were reloaded
+// or unloaded.
+type Changes struct {
+	ReloadedModules []string
+	RemovedModules  []string
+	Added           []SymbolChange
+	Removed         []SymbolChange
+	Moved           []SymbolChange
+}
+
+// Empty returns true if no modules or symbols were changed.
+func (c *Changes) Empty() bool {
+	return len(c.ReloadedModules) == 0 && len(c.RemovedModules) == 0 &&
+		len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Moved) == 0
+}
+
+func (c *Changes) diff(before, after map[string]OID) {
+	for name, oldOID := range before {
+		if newOID, ok := after[name]; !ok {
+			c.Removed = append(c.Removed, SymbolChange{Name: name, OldOID: oldOID})
+		} else if !oldOID.Equal(newOID) {
+			c.Moved = append(c.Moved, SymbolChange{Name: name, OldOID: oldOID, NewOID: newOID})
+		}
+	}
+	for name, newOID := range after {
+		if _, ok := before[name]; !ok {
+			c.Added = append(c.Added, SymbolChange{Name: name, NewOID: newOID})
+		}
+	}
+	for _, list := range [][]SymbolChange{c.Added, c.Removed, c.Moved} {
+		sort.Slice(list, func(i, j int) bool {
+			return list[i].Name < list[j].Name
+		})
+	}
+}
+
+// Reload rescans the MIB directories and brings the loaded modules up to date
+// with their files. Modules whose files have been modified or replaced are parsed
+// again and modules whose files have been removed are unloaded. The symbol tree
+// is then rebuilt, so modules that import from a changed module are re-indexed.
+//
+// If an error occurs, for example because a loaded module imports from a module
+// that has been removed, the MIB is left unchanged.
+func (mib *MIB) Reload() (*Changes, error) {
+	scanMods, err := mib.scanDirs()
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &Changes{}
+	modules := make(map[string]*Module, len(scanMods))
+	for modName, mod := range scanMods {
+		modules[modName] = mod
+	}
+	var loadNames []string
+	for _, modName := range mib.loadOrder {
+		mod := mib.Modules[modName]
+		newMod, ok := scanMods[modName]
+		switch {
+		case !ok:
+			mib.report(Diagnostic{Kind: DiagFileRemoved, Module: modName, File: mod.File})
+			changes.RemovedModules = append(changes.RemovedModules, modName)
+			continue
+		case mod.File != newMod.File:
+			mib.report(Diagnostic{Kind: DiagFileReplaced, Module: modName, File: newMod.File, Related: mod.File})
+			changes.ReloadedModules = append(changes.ReloadedModules, modName)
+		case !mod.modTime.Equal(newMod.modTime) || mod.size != newMod.size:
+			mib.report(Diagnostic{Kind: DiagFileModified, Module: modName, File: mod.File})
+			changes.ReloadedModules = append(changes.ReloadedModules, modName)
+		default:
+			modules[modName] = mod
+		}
+		loadNames = append(loadNames, modName)
+	}
+
+	if len(changes.ReloadedModules) == 0 && len(changes.RemovedModules) == 0 {
+		mib.Modules = modules
+		return changes, nil
+	}
+
+	err = mib.rebuild(modules, loadNames, changes)
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// rebuild loads the modules listed by modNames from the modules map into a new
+// symbol tree and then replaces the contents of the MIB with it. The tree is
+// built on the side so that the MIB is untouched on error. Modules that have
+// already been parsed keep their parse results. The symbol differences between
+// the old and new tree are added to changes.
+func (mib *MIB) rebuild(modules map[string]*Module, modNames []string, changes *Changes) error {
+	next := &MIB{
+		Modules:          modules,
+		Debug:            mib.Debug,
+		Diagnostics:      mib.Diagnostics,
+		dirs:             mib.dirs,
+		aliases:          mib.aliases,
+		duplicatePolicy:  mib.duplicatePolicy,
+		preferredModules: mib.preferredModules,
+	}
+	next.resetTree()
+	for modName, mod := range modules {
+		if mod.IsLoaded {
+			m := *mod
+			m.IsLoaded = false
+			m.Symbols = nil
+			modules[modName] = &m
+		}
+	}
+	for _, modName := range modNames {
+		err := next.loadModule(modName)
+		if err != nil {
+			return err
+		}
+	}
+	err := next.indexModules(0)
+	if err != nil {
+		return err
+	}
+
+	before := mib.symbolOIDs()
+	mib.Modules = next.Modules
+	mib.Root = next.Root
+	mib.Symbols = next.Symbols
+	mib.definitions = next.definitions
+	mib.loadOrder = next.loadOrder
+	mib.usedAliases = next.usedAliases
+	mib.textIndex = nil
+	changes.diff(before, mib.symbolOIDs())
+	return nil
+}
+
+// symbolOIDs returns the OIDs of all symbols in the loaded modules keyed
+// by their Module::name strings.
+func (mib *MIB) symbolOIDs() map[string]OID {
+	oids := make(map[string]OID)
+	for _, modName := range mib.loadOrder {
+		for _, sym := range mib.Modules[modName].Symbols {
+			oids[sym.String()] = mib.symbolOID(sym)
+		}
+	}
+	return oids
+}
+
+// A Watcher periodically reloads a MIB. Watchers are created by MIB.Watch.
+type Watcher struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Watch starts a goroutine that calls Reload every interval. The function fn
+// is called with the result whenever the reload changed the MIB or failed.
+// An error is only reported again once it changes or a reload succeeds, so
+// that a module left broken does not report the same error every interval.
+//
+// Reload modifies the MIB from the watcher's goroutine. If lock is not nil it
+// is held during each reload; use the same lock to guard other uses of the MIB.
+// The lock is released before fn is called.
+func (mib *MIB) Watch(interval time.Duration, lock sync.Locker, fn func(*Changes, error)) *Watcher {
+	w := &Watcher{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go func() {
+		defer close(w.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var lastErr string
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+				if lock != nil {
+					lock.Lock()
+				}
+				changes, err := mib.Reload()
+				if lock != nil {
+					lock.Unlock()
+				}
+				if err == nil {
+					lastErr = ""
+				} else if err.Error() == lastErr {
+					continue
+				} else {
+					lastErr = err.Error()
+				}
+				if err != nil || !changes.Empty() {
+					fn(changes, err)
+				}
+			}
+		}
+	}()
+	return w
+}
+
+// Stop stops the watcher and waits for a reload in progress to finish.
+func (w *Watcher) Stop() {
+	close(w.stop)
+	<-w.done
+}
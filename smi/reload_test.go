@@ -0,0 +1,321 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hallidave/mibtool/smi"
+)
+
+const reloadMIBv1 = `RELOAD-MIB DEFINITIONS ::= BEGIN
+IMPORTS enterprises FROM SNMPv2-SMI;
+reloadRoot OBJECT IDENTIFIER ::= { enterprises 9999 }
+reloadMoved OBJECT IDENTIFIER ::= { reloadRoot 1 }
+reloadGone OBJECT IDENTIFIER ::= { reloadRoot 2 }
+END
+`
+
+const reloadMIBv2 = `RELOAD-MIB DEFINITIONS ::= BEGIN
+IMPORTS enterprises FROM SNMPv2-SMI;
+reloadRoot OBJECT IDENTIFIER ::= { enterprises 9999 }
+reloadMoved OBJECT IDENTIFIER ::= { reloadRoot 3 }
+reloadNew OBJECT IDENTIFIER ::= { reloadRoot 4 }
+END
+`
+
+const reloadUserMIB = `RELOAD-USER-MIB DEFINITIONS ::= BEGIN
+IMPORTS reloadMoved FROM RELOAD-MIB;
+reloadUser OBJECT IDENTIFIER ::= { reloadMoved 1 }
+END
+`
+
+func setupReloadDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "mibtool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	smiText, err := ioutil.ReadFile(filepath.Join("testdata", "SNMPv2-SMI"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeMIBFile(t, dir, "SNMPv2-SMI", smiText)
+	writeMIBFile(t, dir, "RELOAD-MIB", []byte(reloadMIBv1))
+	writeMIBFile(t, dir, "RELOAD-USER-MIB", []byte(reloadUserMIB))
+	return dir
+}
+
+func writeMIBFile(t *testing.T, dir, name string, text []byte) {
+	filename := filepath.Join(dir, name)
+	var modTime time.Time
+	if fi, err := os.Stat(filename); err == nil {
+		// Make sure the change is visible on file systems with a coarse
+		// modification time.
+		modTime = fi.ModTime().Add(time.Second)
+	}
+	err := ioutil.WriteFile(filename, text, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !modTime.IsZero() {
+		err = os.Chtimes(filename, modTime, modTime)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReloadUnchanged(t *testing.T) {
+	dir := setupReloadDir(t)
+	defer os.RemoveAll(dir)
+
+	mib := smi.NewMIB(dir)
+	err := mib.LoadModules("RELOAD-USER-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := mib.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changes.Empty() {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+}
+
+func TestReloadModified(t *testing.T) {
+	dir := setupReloadDir(t)
+	defer os.RemoveAll(dir)
+
+	mib := smi.NewMIB(dir)
+	err := mib.LoadModules("RELOAD-USER-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeMIBFile(t, dir, "RELOAD-MIB", []byte(reloadMIBv2))
+	changes, err := mib.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(changes.ReloadedModules) != 1 || changes.ReloadedModules[0] != "RELOAD-MIB" {
+		t.Errorf("reloaded modules: got %v", changes.ReloadedModules)
+	}
+	if len(changes.Added) != 1 || changes.Added[0].Name != "RELOAD-MIB::reloadNew" {
+		t.Errorf("added: got %v", changes.Added)
+	}
+	if len(changes.Removed) != 1 || changes.Removed[0].Name != "RELOAD-MIB::reloadGone" {
+		t.Errorf("removed: got %v", changes.Removed)
+	}
+	expectedMoved := []smi.SymbolChange{
+		{"RELOAD-MIB::reloadMoved", smi.OID{1, 3, 6, 1, 4, 1, 9999, 1}, smi.OID{1, 3, 6, 1, 4, 1, 9999, 3}},
+		{"RELOAD-USER-MIB::reloadUser", smi.OID{1, 3, 6, 1, 4, 1, 9999, 1, 1}, smi.OID{1, 3, 6, 1, 4, 1, 9999, 3, 1}},
+	}
+	if len(changes.Moved) != len(expectedMoved) {
+		t.Fatalf("moved: got %v", changes.Moved)
+	}
+	for i, expected := range expectedMoved {
+		moved := changes.Moved[i]
+		if moved.Name != expected.Name || !moved.OldOID.Equal(expected.OldOID) || !moved.NewOID.Equal(expected.NewOID) {
+			t.Errorf("moved: got %v, expected %v", moved, expected)
+		}
+	}
+
+	if _, err := mib.OID("reloadGone"); err == nil {
+		t.Error("expected reloadGone to be removed")
+	}
+	sym, _ := mib.Symbol(smi.OID{1, 3, 6, 1, 4, 1, 9999, 1})
+	if sym.Name != "reloadRoot" {
+		t.Errorf("expected old reloadMoved node to be removed from tree, got %v", sym)
+	}
+	oid, err := mib.OID("reloadUser")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !oid.Equal(smi.OID{1, 3, 6, 1, 4, 1, 9999, 3, 1}) {
+		t.Errorf("got %s", oid)
+	}
+}
+
+func TestReloadRemoved(t *testing.T) {
+	dir := setupReloadDir(t)
+	defer os.RemoveAll(dir)
+
+	mib := smi.NewMIB(dir)
+	err := mib.LoadModules("RELOAD-USER-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// RELOAD-USER-MIB still imports from the removed module
+	err = os.Remove(filepath.Join(dir, "RELOAD-MIB"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mib.Reload(); err == nil {
+		t.Fatal("expected error reloading without RELOAD-MIB")
+	}
+	if _, err := mib.OID("reloadUser"); err != nil {
+		t.Errorf("expected MIB to be unchanged: %v", err)
+	}
+
+	err = os.Remove(filepath.Join(dir, "RELOAD-USER-MIB"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := mib.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes.RemovedModules) != 2 {
+		t.Errorf("removed modules: got %v", changes.RemovedModules)
+	}
+	if len(changes.Removed) != 4 {
+		t.Errorf("removed: got %v", changes.Removed)
+	}
+	if _, ok := mib.Symbols["reloadRoot"]; ok {
+		t.Error("expected reloadRoot to be removed")
+	}
+	if _, ok := mib.Modules["RELOAD-MIB"]; ok {
+		t.Error("expected RELOAD-MIB to be removed")
+	}
+}
+
+func TestWatch(t *testing.T) {
+	dir := setupReloadDir(t)
+	defer os.RemoveAll(dir)
+
+	mib := smi.NewMIB(dir)
+	err := mib.LoadModules("RELOAD-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var lock sync.Mutex
+	notified := make(chan *smi.Changes, 1)
+	w := mib.Watch(10*time.Millisecond, &lock, func(changes *smi.Changes, err error) {
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		notified <- changes
+	})
+	defer w.Stop()
+
+	lock.Lock()
+	writeMIBFile(t, dir, "RELOAD-MIB", []byte(reloadMIBv2))
+	lock.Unlock()
+
+	select {
+	case changes := <-notified:
+		if len(changes.Added) != 1 {
+			t.Errorf("added: got %v", changes.Added)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for reload")
+	}
+}
+
+func TestWatchRepeatedError(t *testing.T) {
+	dir := setupReloadDir(t)
+	defer os.RemoveAll(dir)
+
+	mib := smi.NewMIB(dir)
+	err := mib.LoadModules("RELOAD-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each call sends its error, or nil for changes
+	var lock sync.Mutex
+	results := make(chan error, 10)
+	w := mib.Watch(5*time.Millisecond, &lock, func(changes *smi.Changes, err error) {
+		results <- err
+	})
+	defer w.Stop()
+
+	write := func(text string) error {
+		t.Helper()
+		lock.Lock()
+		writeMIBFile(t, dir, "RELOAD-MIB", []byte(text))
+		lock.Unlock()
+		select {
+		case err := <-results:
+			return err
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for reload")
+		}
+		return nil
+	}
+
+	broken := "RELOAD-MIB DEFINITIONS ::= BEGIN\n"
+	if err := write(broken); err == nil {
+		t.Fatal("expected an error for the broken module")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if n := len(results); n != 0 {
+		t.Errorf("got %d more reports of the same error", n)
+	}
+
+	// The error is reported again after a reload succeeds
+	if err := write(reloadMIBv2); err != nil {
+		t.Fatal(err)
+	}
+	if err := write(broken); err == nil {
+		t.Fatal("expected the error to be reported again")
+	}
+}
+
+func TestLoadModulesIncremental(t *testing.T) {
+	dir := setupReloadDir(t)
+	defer os.RemoveAll(dir)
+
+	mib := smi.NewMIB(dir)
+	err := mib.LoadModules("RELOAD-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := mib.Root
+	sym := mib.Symbols["reloadMoved"]
+
+	// A later load adds to the tree without picking up the modified file
+	writeMIBFile(t, dir, "RELOAD-MIB", []byte(reloadMIBv2))
+	err = mib.LoadModules("RELOAD-USER-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mib.Root != root || mib.Symbols["reloadMoved"] != sym {
+		t.Error("expected symbols of loaded modules to be kept")
+	}
+	oid, err := mib.OID("reloadUser")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !oid.Equal(smi.OID{1, 3, 6, 1, 4, 1, 9999, 1, 1}) {
+		t.Errorf("got %s", oid)
+	}
+
+	err = mib.LoadModules("NO-SUCH-MIB")
+	if err == nil {
+		t.Error("expected error loading missing module")
+	}
+	if !mib.Modules["RELOAD-USER-MIB"].IsLoaded {
+		t.Error("expected earlier loads to be kept after an error")
+	}
+
+	changes, err := mib.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes.ReloadedModules) != 1 || changes.ReloadedModules[0] != "RELOAD-MIB" {
+		t.Errorf("reloaded modules: got %v", changes.ReloadedModules)
+	}
+}
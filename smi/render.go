@@ -0,0 +1,310 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// A Varbind is an object instance and its value, as carried in an SNMP
+// message. The value is nil for NULL, an integer type for the integer SMI
+// types, a []byte or string for OCTET STRING, Opaque and IpAddress values
+// and BITS, and an OID for OBJECT IDENTIFIER values. A Value returned by
+// DecodeValue may also be used.
+type Varbind struct {
+	OID   OID
+	Value interface{}
+}
+
+// A RenderedVarbind is a varbind described with the objects of the MIB.
+// Name is the instance name as returned by SymbolString and Index holds
+// the decoded values of the index of a table column, as FormatOID renders
+// them. Symbol is nil if the object ID is not below any loaded symbol.
+type RenderedVarbind struct {
+	OID    OID
+	Name   string
+	Symbol *Symbol
+	Index  []string
+	Value  string
+	Units  string
+}
+
+// String returns the varbind in the format "name = value units".
+func (r RenderedVarbind) String() string {
+	if r.Units == "" {
+		return r.Name + " = " + r.Value
+	}
+	return r.Name + " = " + r.Value + " " + r.Units
+}
+
+// RenderVarbind describes the value of the object instance oid using the
+// definition of the object: enumerated values and BITS are shown with their
+// names as in up(1), TimeTicks as a duration, IpAddress values in dotted-quad
+// notation, object IDs with SymbolString and other values with the
+// DISPLAY-HINT of their textual convention, as by FormatValue. Values that do
+// not match the syntax of the object are shown as they are.
+//
+// InetAddress values are shown according to the length of the address. Use
+// RenderVarbinds to take the InetAddressType of the same table row into
+// account.
+func (mib *MIB) RenderVarbind(oid OID, value interface{}) RenderedVarbind {
+	return mib.render(oid, value, nil)
+}
+
+// RenderVarbinds renders each of the varbinds as RenderVarbind does. The
+// address type of an InetAddress value is taken from the InetAddressType
+// object of the same row when the varbinds include it.
+func (mib *MIB) RenderVarbinds(varbinds []Varbind) []RenderedVarbind {
+	values := make(map[string]interface{}, len(varbinds))
+	for _, vb := range varbinds {
+		values[vb.OID.String()] = vb.Value
+	}
+	rendered := make([]RenderedVarbind, len(varbinds))
+	for i, vb := range varbinds {
+		rendered[i] = mib.render(vb.OID, vb.Value, values)
+	}
+	return rendered
+}
+
+func (mib *MIB) render(oid OID, value interface{}, values map[string]interface{}) RenderedVarbind {
+	value = plainValue(value)
+	r := RenderedVarbind{OID: oid, Name: mib.SymbolString(oid)}
+	if len(oid) == 0 {
+		r.Value = mib.renderPlain(value)
+		return r
+	}
+	sym, idx := mib.Symbol(oid)
+	r.Symbol = sym
+	if sym == nil {
+		r.Value = mib.renderPlain(value)
+		return r
+	}
+	if sym.IsColumn() && len(idx) > 0 {
+		r.Index, _ = mib.decodeIndex(sym.Parent, idx, false)
+	}
+	if sym.Node == nil || sym.Node.Syntax == nil || value == nil {
+		r.Value = mib.renderPlain(value)
+		return r
+	}
+	r.Units = sym.Node.Units
+	syntax, ok := mib.resolveSyntax(sym.Module, *sym.Node.Syntax)
+	if !ok {
+		r.Value = mib.renderPlain(value)
+		return r
+	}
+	if s, ok := mib.renderTyped(sym, syntax, idx, value, values); ok {
+		r.Value = s
+	} else {
+		r.Value = mib.renderPlain(value)
+	}
+	return r
+}
+
+// renderTyped renders a value using the resolved syntax of its object. It
+// returns false if the value does not suit the syntax.
+func (mib *MIB) renderTyped(sym *Symbol, syntax Syntax, idx OID, value interface{}, values map[string]interface{}) (string, bool) {
+	octets, isOctets := octetsValue(value)
+	switch {
+	case syntax.Type == "BITS" && isOctets:
+		return renderBits(syntax, octets), true
+	case syntax.Type == "IpAddress" && isOctets && len(octets) == 4:
+		return net.IP(octets).String(), true
+	case syntax.Type == "OBJECT IDENTIFIER":
+		if oid, ok := value.(OID); ok {
+			return mib.SymbolString(oid), true
+		}
+		return "", false
+	case isOctets && mib.derivesFrom(sym, "InetAddress"):
+		return renderInetAddress(mib.inetAddressType(sym, idx, values), octets), true
+	case isOctets:
+		s, err := mib.FormatValue(sym, octets)
+		return s, err == nil
+	}
+
+	n, ok := integerValue(value)
+	if !ok {
+		if u, isUint := value.(uint64); isUint && mib.DisplayHint(sym) == "" {
+			return strconv.FormatUint(u, 10), true
+		}
+		return "", false
+	}
+	switch {
+	case len(syntax.Enums) > 0:
+		for _, enum := range syntax.Enums {
+			if enum.Value == n {
+				return fmt.Sprintf("%s(%d)", enum.Name, n), true
+			}
+		}
+		return strconv.FormatInt(n, 10), true
+	case syntax.Type == "TimeTicks":
+		return renderTimeTicks(n), true
+	}
+	s, err := mib.FormatValue(sym, n)
+	return s, err == nil
+}
+
+// renderPlain renders a value without knowledge of its object.
+func (mib *MIB) renderPlain(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case OID:
+		return mib.SymbolString(v)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	}
+	if octets, ok := octetsValue(value); ok {
+		s, _ := formatOctetsValue("", octets)
+		return s
+	}
+	if n, ok := integerValue(value); ok {
+		return strconv.FormatInt(n, 10)
+	}
+	return fmt.Sprint(value)
+}
+
+func octetsValue(value interface{}) ([]byte, bool) {
+	switch v := plainValue(value).(type) {
+	case []byte:
+		return v, true
+	case string:
+		return []byte(v), true
+	}
+	return nil, false
+}
+
+// renderBits lists the names of the bits that are set in a BITS value.
+func renderBits(syntax Syntax, octets []byte) string {
+	var names []string
+	for bit := 0; bit < 8*len(octets); bit++ {
+		if octets[bit/8]&(0x80>>(bit%8)) == 0 {
+			continue
+		}
+		name := strconv.Itoa(bit)
+		for _, enum := range syntax.Enums {
+			if enum.Value == int64(bit) {
+				name = fmt.Sprintf("%s(%d)", enum.Name, bit)
+				break
+			}
+		}
+		names = append(names, name)
+	}
+	return "{ " + strings.Join(names, ", ") + " }"
+}
+
+// renderTimeTicks shows hundredths of a second as a duration in the format
+// used by net-snmp, as in (9038405) 1 day, 1:06:24.05.
+func renderTimeTicks(ticks int64) string {
+	t := ticks
+	days := t / 8640000
+	t %= 8640000
+	hours := t / 360000
+	t %= 360000
+	minutes := t / 6000
+	t %= 6000
+	duration := fmt.Sprintf("%d:%02d:%02d.%02d", hours, minutes, t/100, t%100)
+	switch {
+	case days == 1:
+		duration = "1 day, " + duration
+	case days > 1:
+		duration = fmt.Sprintf("%d days, %s", days, duration)
+	}
+	return fmt.Sprintf("(%d) %s", ticks, duration)
+}
+
+// derivesFrom returns true if the syntax of the object sym is the type name
+// or a type derived from it.
+func (mib *MIB) derivesFrom(sym *Symbol, name string) bool {
+	mod := sym.Module
+	typeName := sym.Node.Syntax.Type
+	for depth := 0; depth < MaxTypeDepth && !smiBaseTypes[typeName]; depth++ {
+		if typeName == name {
+			return true
+		}
+		var t *Type
+		mod, t = mib.findType(mod, typeName)
+		if t == nil {
+			return false
+		}
+		typeName = t.Syntax.Type
+	}
+	return false
+}
+
+// inetAddressType returns the value of the InetAddressType object that
+// describes the InetAddress column sym in the row with index idx, or -1 if
+// it is not among values. RFC 4001 requires the InetAddressType object to be
+// in the same row; if the row has several, the one whose name is the name of
+// sym followed by Type is used.
+func (mib *MIB) inetAddressType(sym *Symbol, idx OID, values map[string]interface{}) int64 {
+	if values == nil || !sym.IsColumn() {
+		return -1
+	}
+	row := sym.Parent
+	var typeColumn *Symbol
+	for _, id := range sortedChildIDs(row) {
+		column := row.ChildByID[id]
+		if column.Node == nil || column.Node.Syntax == nil || !mib.derivesFrom(column, "InetAddressType") {
+			continue
+		}
+		if typeColumn == nil || column.Name == sym.Name+"Type" {
+			typeColumn = column
+		}
+	}
+	if typeColumn == nil {
+		return -1
+	}
+	oid := mib.symbolOID(typeColumn).Append(idx...)
+	n, ok := integerValue(values[oid.String()])
+	if !ok {
+		return -1
+	}
+	return n
+}
+
+// InetAddressType values defined by RFC 4001
+const (
+	inetIPv4  = 1
+	inetIPv6  = 2
+	inetIPv4z = 3
+	inetIPv6z = 4
+	inetDNS   = 16
+)
+
+// renderInetAddress shows an InetAddress value of the given InetAddressType,
+// or of a type chosen by the length of the address if addrType is -1. IPv6
+// addresses are shown in their shortest form and zone indexes follow a %.
+func renderInetAddress(addrType int64, octets []byte) string {
+	if addrType == -1 {
+		switch len(octets) {
+		case net.IPv4len:
+			addrType = inetIPv4
+		case net.IPv6len:
+			addrType = inetIPv6
+		case net.IPv4len + 4:
+			addrType = inetIPv4z
+		case net.IPv6len + 4:
+			addrType = inetIPv6z
+		}
+	}
+	switch {
+	case addrType == inetIPv4 && len(octets) == net.IPv4len,
+		addrType == inetIPv6 && len(octets) == net.IPv6len:
+		return net.IP(octets).String()
+	case addrType == inetIPv4z && len(octets) == net.IPv4len+4,
+		addrType == inetIPv6z && len(octets) == net.IPv6len+4:
+		ip, zone := octets[:len(octets)-4], octets[len(octets)-4:]
+		zoneIndex := uint32(zone[0])<<24 | uint32(zone[1])<<16 | uint32(zone[2])<<8 | uint32(zone[3])
+		return fmt.Sprintf("%s%%%d", net.IP(ip), zoneIndex)
+	case addrType == inetDNS:
+		return string(octets)
+	}
+	s, _ := formatOctetsValue("", octets)
+	return s
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hallidave/mibtool/smi"
+)
+
+func TestRenderVarbind(t *testing.T) {
+	mib := smi.NewMIB("testdata")
+	err := mib.LoadModules("IF-MIB", "HOST-RESOURCES-MIB", "BGP4-MIB", "RMON2-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{"IF-MIB::ifOperStatus.3", 1, "IF-MIB::ifOperStatus.3 = up(1)"},
+		{"IF-MIB::ifOperStatus.3", int64(42), "IF-MIB::ifOperStatus.3 = 42"},
+		{"IF-MIB::ifDescr.3", []byte("eth0"), "IF-MIB::ifDescr.3 = eth0"},
+		{"IF-MIB::ifPhysAddress.3", []byte{0, 0x1a, 0x2b, 0x3c, 0x4d, 0x5e}, "IF-MIB::ifPhysAddress.3 = 00:1a:2b:3c:4d:5e"},
+		{"IF-MIB::ifLastChange.3", uint32(9038405), "IF-MIB::ifLastChange.3 = (9038405) 1 day, 1:06:24.05"},
+		{"SNMPv2-MIB::sysUpTime.0", 12345, "SNMPv2-MIB::sysUpTime.0 = (12345) 0:02:03.45"},
+		{"SNMPv2-MIB::sysObjectID.0", smi.OID{1, 3, 6, 1, 2, 1, 2, 2, 1, 1}, "SNMPv2-MIB::sysObjectID.0 = IF-MIB::ifIndex"},
+		{"IF-MIB::ifHCInOctets.3", uint64(1) << 63, "IF-MIB::ifHCInOctets.3 = 9223372036854775808"},
+		{"BGP4-MIB::bgpPeerIdentifier.192.0.2.1", []byte{192, 0, 2, 1}, "BGP4-MIB::bgpPeerIdentifier.192.0.2.1 = 192.0.2.1"},
+		{"HOST-RESOURCES-MIB::hrMemorySize.0", 2048, "HOST-RESOURCES-MIB::hrMemorySize.0 = 2048 KBytes"},
+		{"HOST-RESOURCES-MIB::hrSystemDate.0", []byte{0x07, 0xe8, 3, 1, 10, 0, 0, 0, '+', 1, 0}, "HOST-RESOURCES-MIB::hrSystemDate.0 = 2024-3-1,10:0:0.0,+1:0"},
+		{"RMON2-MIB::protocolDirType.1.2", []byte{0xc0}, "RMON2-MIB::protocolDirType.1.2 = { extensible(0), addressRecognitionCapable(1) }"},
+		{"RMON2-MIB::protocolDirType.1.2", []byte{0x84}, "RMON2-MIB::protocolDirType.1.2 = { extensible(0), 5 }"},
+		{"IF-MIB::ifOperStatus.3", []byte("up"), "IF-MIB::ifOperStatus.3 = up"},
+		{"IF-MIB::ifOperStatus.3", nil, "IF-MIB::ifOperStatus.3 = NULL"},
+		{"1.3.6.1.4.1.99999.1", []byte{0xff, 0x01}, "SNMPv2-SMI::enterprises.99999.1 = ff 01"},
+	}
+	for _, test := range tests {
+		oid, err := mib.OID(test.name)
+		if err != nil {
+			oid, err = smi.ParseOID(test.name)
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		r := mib.RenderVarbind(oid, test.value)
+		if s := r.String(); s != test.expected {
+			t.Errorf("%s: got %q, expected %q", test.name, s, test.expected)
+		}
+	}
+
+	if r := mib.RenderVarbind(smi.OID{}, 7); r.Value != "7" || r.Symbol != nil {
+		t.Errorf("empty OID: got %+v", r)
+	}
+
+	r := mib.RenderVarbind(smi.OID{1, 3, 6, 1, 2, 1, 31, 1, 2, 1, 3, 1, 2}, 1)
+	if strings.Join(r.Index, ",") != "1,2" || r.Symbol.Name != "ifStackStatus" || r.Value != "active(1)" {
+		t.Errorf("got %+v", r)
+	}
+}
+
+func TestRenderDecodedValue(t *testing.T) {
+	mib := smi.NewMIB("testdata")
+	err := mib.LoadModules("IF-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		value    smi.Value
+		expected string
+	}{
+		{"IF-MIB::ifOperStatus.3", smi.Value{Type: smi.ValueInteger, Int: 1}, "up(1)"},
+		{"IF-MIB::ifDescr.3", smi.Value{Type: smi.ValueOctetString, Bytes: []byte("eth0")}, "eth0"},
+		{"IF-MIB::ifLastChange.3", smi.Value{Type: smi.ValueTimeTicks, Uint: 9038405}, "(9038405) 1 day, 1:06:24.05"},
+		{"IF-MIB::ifHCInOctets.3", smi.Value{Type: smi.ValueCounter64, Uint: 1 << 63}, "9223372036854775808"},
+		{"SNMPv2-MIB::sysObjectID.0", smi.Value{Type: smi.ValueObjectID, OID: smi.OID{1, 3, 6, 1, 2, 1, 2, 2, 1, 1}}, "IF-MIB::ifIndex"},
+		{"IF-MIB::ifOperStatus.3", smi.Value{Type: smi.ValueNull}, "NULL"},
+		{"IF-MIB::ifOperStatus.3", smi.Value{Type: smi.ValueNoSuchInstance}, "noSuchInstance"},
+		{"1.3.6.1.4.1.99999.1", smi.Value{Type: smi.ValueGauge32, Uint: 7}, "7"},
+	}
+	for _, test := range tests {
+		oid, err := mib.OID(test.name)
+		if err != nil {
+			oid, err = smi.ParseOID(test.name)
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		b, err := smi.EncodeValue(test.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		value, _, err := smi.DecodeValue(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r := mib.RenderVarbind(oid, value); r.Value != test.expected {
+			t.Errorf("%s: got %q, expected %q", test.name, r.Value, test.expected)
+		}
+	}
+}
+
+func TestRenderVarbindsInetAddress(t *testing.T) {
+	mib := smi.NewMIB("testdata")
+	err := mib.LoadModules("ALARM-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addrType, err := mib.OID(`ALARM-MIB::alarmActiveEngineAddressType["list"]['07E8030100000000'H][1]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := mib.OID(`ALARM-MIB::alarmActiveEngineAddress["list"]['07E8030100000000'H][1]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ipv6 := []byte{0x20, 0x01, 0x0d, 0xb8, 15: 1}
+	tests := []struct {
+		varbinds []smi.Varbind
+		expected string
+	}{
+		{[]smi.Varbind{{OID: addr, Value: ipv6}}, "2001:db8::1"},
+		{[]smi.Varbind{{OID: addrType, Value: 2}, {OID: addr, Value: ipv6}}, "2001:db8::1"},
+		{[]smi.Varbind{{OID: addrType, Value: 1}, {OID: addr, Value: []byte{192, 0, 2, 1}}}, "192.0.2.1"},
+		{[]smi.Varbind{{OID: addrType, Value: 4}, {OID: addr, Value: append(ipv6, 0, 0, 0, 3)}}, "2001:db8::1%3"},
+		{[]smi.Varbind{{OID: addrType, Value: 16}, {OID: addr, Value: []byte("example.com")}}, "example.com"},
+		{[]smi.Varbind{{OID: addrType, Value: 1}, {OID: addr, Value: ipv6}}, "20 01 0d b8 00 00 00 00 00 00 00 00 00 00 00 01"},
+	}
+	for i, test := range tests {
+		rendered := mib.RenderVarbinds(test.varbinds)
+		r := rendered[len(rendered)-1]
+		if r.Value != test.expected {
+			t.Errorf("%d: got %q, expected %q", i, r.Value, test.expected)
+		}
+		if len(r.Index) != 3 || r.Index[0] != `"list"` {
+			t.Errorf("%d: got index %v", i, r.Index)
+		}
+	}
+	rendered := mib.RenderVarbinds([]smi.Varbind{{OID: addrType, Value: 16}})
+	if rendered[0].Value != "dns(16)" {
+		t.Errorf("got %q, expected dns(16)", rendered[0].Value)
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// MatchKind describes how a symbol name matched a search query. Better
+// matches have lower values.
+type MatchKind int
+
+// MatchKind values in order of rank
+const (
+	MatchExact     MatchKind = iota // the name is the query
+	MatchFold                       // the name is the query ignoring case
+	MatchPrefix                     // the name starts with the query, ignoring case
+	MatchSubstring                  // the name contains the query, ignoring case
+	MatchRegexp                     // the name matches the query as a regular expression
+	MatchFuzzy                      // the name is within an edit distance of the query
+)
+
+var matchKindNames = map[MatchKind]string{
+	MatchExact:     "exact",
+	MatchFold:      "case-insensitive",
+	MatchPrefix:    "prefix",
+	MatchSubstring: "substring",
+	MatchRegexp:    "regexp",
+	MatchFuzzy:     "fuzzy",
+}
+
+func (k MatchKind) String() string {
+	if name, ok := matchKindNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("MatchKind(%d)", int(k))
+}
+
+// SearchOptions control a symbol search. Empty filter lists select every
+// symbol. Status and Access are compared with the STATUS and MAX-ACCESS
+// clauses of the symbol definitions, such as "current" or "read-write".
+type SearchOptions struct {
+	Modules []string
+	Types   []NodeType
+	Status  []string
+	Access  []string
+
+	// Regexp makes the query a regular expression instead of a name.
+	Regexp bool
+
+	// MaxDistance is the largest edit distance between the query and a name
+	// for a fuzzy match, ignoring case. Fuzzy matching is off if it is 0.
+	MaxDistance int
+
+	// Limit is the maximum number of results, or 0 for no limit.
+	Limit int
+}
+
+// A SearchResult is a symbol found by Search. Distance is the edit distance
+// of a fuzzy match.
+type SearchResult struct {
+	Symbol   *Symbol
+	OID      OID
+	Match    MatchKind
+	Distance int
+}
+
+// Search finds the symbols of the loaded modules whose names match query.
+// The results are ranked by MatchKind, then by edit distance and then by
+// OID. Symbols defined by more than one module are found once per module.
+// An error is returned if the query is not a valid regular expression.
+func (mib *MIB) Search(query string, opts SearchOptions) ([]SearchResult, error) {
+	var re *regexp.Regexp
+	if opts.Regexp {
+		var err error
+		re, err = regexp.Compile(query)
+		if err != nil {
+			return nil, err
+		}
+	}
+	modules := make(map[string]bool)
+	for _, modName := range opts.Modules {
+		modules[mib.moduleName(modName)] = true
+	}
+
+	lowerQuery := strings.ToLower(query)
+	var results []SearchResult
+	for _, modName := range mib.loadOrder {
+		if len(modules) > 0 && !modules[modName] {
+			continue
+		}
+		for _, sym := range mib.Modules[modName].Symbols {
+			if !opts.selects(sym) {
+				continue
+			}
+			result := SearchResult{Symbol: sym}
+			if re != nil {
+				if !re.MatchString(sym.Name) {
+					continue
+				}
+				result.Match = MatchRegexp
+			} else if !matchName(sym.Name, query, lowerQuery, opts.MaxDistance, &result) {
+				continue
+			}
+			result.OID = mib.symbolOID(sym)
+			results = append(results, result)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Match != b.Match {
+			return a.Match < b.Match
+		}
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		if c := a.OID.Compare(b.OID); c != 0 {
+			return c < 0
+		}
+		return a.Symbol.String() < b.Symbol.String()
+	})
+	if opts.Limit > 0 && len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+	return results, nil
+}
+
+func (opts *SearchOptions) selects(sym *Symbol) bool {
+	if len(opts.Types) > 0 && !slices.Contains(opts.Types, sym.Type) {
+		return false
+	}
+	var status, access string
+	if sym.Node != nil {
+		status = sym.Node.Status
+		access = sym.Node.Access
+	}
+	if len(opts.Status) > 0 && !slices.Contains(opts.Status, status) {
+		return false
+	}
+	if len(opts.Access) > 0 && !slices.Contains(opts.Access, access) {
+		return false
+	}
+	return true
+}
+
+// matchName compares a name with the query, setting the Match and Distance
+// fields of result. It returns false if the name does not match.
+func matchName(name, query, lowerQuery string, maxDistance int, result *SearchResult) bool {
+	lowerName := strings.ToLower(name)
+	switch {
+	case name == query:
+		result.Match = MatchExact
+	case lowerName == lowerQuery:
+		result.Match = MatchFold
+	case strings.HasPrefix(lowerName, lowerQuery):
+		result.Match = MatchPrefix
+	case strings.Contains(lowerName, lowerQuery):
+		result.Match = MatchSubstring
+	default:
+		if maxDistance <= 0 {
+			return false
+		}
+		d := editDistance(lowerName, lowerQuery, maxDistance)
+		if d > maxDistance {
+			return false
+		}
+		result.Match = MatchFuzzy
+		result.Distance = d
+	}
+	return true
+}
+
+// editDistance returns the Levenshtein distance between a and b. Once the
+// distance is known to be more than limit, the result is only known to be
+// greater than limit.
+func editDistance(a, b string, limit int) int {
+	if d := len(a) - len(b); d > limit || -d > limit {
+		return limit + 1
+	}
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if cur[j] < rowMin {
+				rowMin = cur[j]
+			}
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi_test
+
+import (
+	"testing"
+
+	"github.com/hallidave/mibtool/smi"
+)
+
+func searchNames(results []smi.SearchResult) []string {
+	names := make([]string, len(results))
+	for i, result := range results {
+		names[i] = result.Symbol.String() + " " + result.Match.String()
+	}
+	return names
+}
+
+func TestSearch(t *testing.T) {
+	mib := smi.NewMIB("testdata")
+	err := mib.LoadModules("IF-MIB", "RMON-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query    string
+		opts     smi.SearchOptions
+		expected []string
+	}{
+		{"ifIndex", smi.SearchOptions{Limit: 3}, []string{
+			"IF-MIB::ifIndex exact",
+			"RMON-MIB::channelIfIndex substring",
+		}},
+		{"IFINDEX", smi.SearchOptions{Limit: 1}, []string{
+			"IF-MIB::ifIndex case-insensitive",
+		}},
+		{"inoctets", smi.SearchOptions{}, []string{
+			"IF-MIB::ifInOctets substring",
+			"RMON-MIB::hostInOctets substring",
+			"RMON-MIB::hostTimeInOctets substring",
+			"IF-MIB::ifHCInOctets substring",
+		}},
+		{"octets", smi.SearchOptions{Modules: []string{"IF-MIB"}, Access: []string{"read-only"}}, []string{
+			"IF-MIB::ifInOctets substring",
+			"IF-MIB::ifOutOctets substring",
+			"IF-MIB::ifHCInOctets substring",
+			"IF-MIB::ifHCOutOctets substring",
+		}},
+		{"ifAdminStatus", smi.SearchOptions{Access: []string{"read-only"}}, []string{}},
+		{"ifInOctet", smi.SearchOptions{Types: []smi.NodeType{smi.NodeNotification}}, []string{}},
+		{"link", smi.SearchOptions{Types: []smi.NodeType{smi.NodeNotification}}, []string{
+			"IF-MIB::linkDown prefix",
+			"IF-MIB::linkUp prefix",
+		}},
+		{"ifTestType", smi.SearchOptions{Status: []string{"deprecated"}}, []string{
+			"IF-MIB::ifTestType exact",
+		}},
+		{"ifTestType", smi.SearchOptions{Status: []string{"current"}}, []string{}},
+		{"^if(In|Out)Octets$", smi.SearchOptions{Regexp: true}, []string{
+			"IF-MIB::ifInOctets regexp",
+			"IF-MIB::ifOutOctets regexp",
+		}},
+		{"ifInOctetz", smi.SearchOptions{}, []string{}},
+		{"ifInOctetz", smi.SearchOptions{MaxDistance: 1}, []string{
+			"IF-MIB::ifInOctets fuzzy",
+		}},
+		{"ifOutOctetz", smi.SearchOptions{MaxDistance: 3, Limit: 2}, []string{
+			"IF-MIB::ifOutOctets fuzzy",
+			"IF-MIB::ifHCOutOctets fuzzy",
+		}},
+	}
+	for _, test := range tests {
+		results, err := mib.Search(test.query, test.opts)
+		if err != nil {
+			t.Errorf("%s: %v", test.query, err)
+			continue
+		}
+		names := searchNames(results)
+		if len(names) != len(test.expected) {
+			t.Errorf("%s %+v: got %v, expected %v", test.query, test.opts, names, test.expected)
+			continue
+		}
+		for i, name := range names {
+			if name != test.expected[i] {
+				t.Errorf("%s %+v: got %v, expected %v", test.query, test.opts, names, test.expected)
+				break
+			}
+		}
+	}
+
+	if _, err := mib.Search("if(", smi.SearchOptions{Regexp: true}); err == nil {
+		t.Error("expected error for invalid regular expression")
+	}
+}
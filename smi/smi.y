@@ -82,6 +82,16 @@ package smi
     idList []string
     imp Import
     impList []Import
+    line int
+    syntax Syntax
+    typeDef Type
+    ranges []Range
+    rng Range
+    number int64
+    namedNumbers []NamedNumber
+    namedNumber NamedNumber
+    indexList []IndexObject
+    indexObject IndexObject
 }
 
 
@@ -212,7 +222,7 @@ package smi
 %type  <err>exportsClause
 %type  <err>macroClause
 %type  <id>macroName
-%type  <typePtr>choiceClause
+%type  <syntax>choiceClause
 %type  <id>typeName
 %type  <id>typeSMI
 %type  <id>typeSMIonly
@@ -221,15 +231,15 @@ package smi
 %type  <err>typeTag
 %type  <id>fuzzy_lowercase_identifier
 %type  <node>valueDeclaration
-%type  <typePtr>conceptualTable
-%type  <typePtr>row
-%type  <typePtr>entryType
+%type  <syntax>conceptualTable
+%type  <syntax>row
+%type  <syntax>entryType
 %type  <listPtr>sequenceItems
 %type  <objectPtr>sequenceItem
-%type  <typePtr>Syntax
+%type  <syntax>Syntax
 %type  <typePtr>sequenceSyntax
-%type  <listPtr>NamedBits
-%type  <namedNumberPtr>NamedBit
+%type  <namedNumbers>NamedBits
+%type  <namedNumber>NamedBit
 %type  <node>objectIdentityClause
 %type  <node>objectTypeClause
 %type  <err>trapTypeClause
@@ -238,42 +248,42 @@ package smi
 %type  <listPtr>VarTypes
 %type  <objectPtr>VarType
 %type  <text>DescrPart
-%type  <access>MaxAccessPart
-%type  <access>MaxOrPIBAccessPart
-%type  <access>PibAccessPart
+%type  <id>MaxAccessPart
+%type  <id>MaxOrPIBAccessPart
+%type  <id>PibAccessPart
 %type  <node>notificationTypeClause
 %type  <node>moduleIdentityClause
-%type  <err>typeDeclaration
-%type  <typePtr>typeDeclarationRHS
-%type  <typePtr>ObjectSyntax
+%type  <typeDef>typeDeclaration
+%type  <typeDef>typeDeclarationRHS
+%type  <syntax>ObjectSyntax
 %type  <typePtr>sequenceObjectSyntax
 %type  <valuePtr>valueofObjectSyntax
-%type  <typePtr>SimpleSyntax
+%type  <syntax>SimpleSyntax
 %type  <valuePtr>valueofSimpleSyntax
 %type  <typePtr>sequenceSimpleSyntax
-%type  <typePtr>ApplicationSyntax
+%type  <syntax>ApplicationSyntax
 %type  <typePtr>sequenceApplicationSyntax
-%type  <listPtr>anySubType
-%type  <listPtr>integerSubType
-%type  <listPtr>octetStringSubType
-%type  <listPtr>ranges
-%type  <rangePtr>range
-%type  <valuePtr>value
-%type  <listPtr>enumSpec
-%type  <listPtr>enumItems
-%type  <namedNumberPtr>enumItem
-%type  <valuePtr>enumNumber
-%type  <status>Status
+%type  <syntax>anySubType
+%type  <ranges>integerSubType
+%type  <ranges>octetStringSubType
+%type  <ranges>ranges
+%type  <rng>range
+%type  <number>value
+%type  <namedNumbers>enumSpec
+%type  <namedNumbers>enumItems
+%type  <namedNumber>enumItem
+%type  <number>enumNumber
+%type  <id>Status
 %type  <status>Status_Capabilities
 %type  <text>DisplayPart
 %type  <text>UnitsPart
-%type  <access>Access
-%type  <index>IndexPart
-%type  <index>MibIndex
-%type  <listPtr>IndexTypes
-%type  <objectPtr>IndexType
-%type  <objectPtr>Index
-%type  <objectPtr>Entry
+%type  <id>Access
+%type  <id>IndexPart
+%type  <indexList>MibIndex
+%type  <indexList>IndexTypes
+%type  <indexObject>IndexType
+%type  <id>Index
+%type  <id>Entry
 %type  <valuePtr>DefValPart
 %type  <valuePtr>Value
 %type  <listPtr>BitsValue
@@ -284,10 +294,10 @@ package smi
 %type  <err>RevisionPart
 %type  <err>Revisions
 %type  <err>Revision
-%type  <listPtr>NotificationObjectsPart
-%type  <listPtr>ObjectGroupObjectsPart
-%type  <listPtr>Objects
-%type  <objectPtr>Object
+%type  <idList>NotificationObjectsPart
+%type  <idList>ObjectGroupObjectsPart
+%type  <idList>Objects
+%type  <id>Object
 %type  <listPtr>NotificationsPart
 %type  <listPtr>Notifications
 %type  <objectPtr>Notification
@@ -377,7 +387,7 @@ module:			moduleName
 			declarationPart
 			tEND
 			{
-				m := Module{Name: $1, Imports: $7, Nodes: $8}
+				m := Module{Name: $1, Imports: $7, Nodes: $8, Types: takeTypes(&smilex)}
 				setModule(&smilex, &m)
 			}
 	;
@@ -440,7 +450,7 @@ import:			importIdentifiers
                         tFROM
                         moduleName
 			{
-				$$ = Import{From: $3, Symbols: $1}
+				$$ = Import{From: $3, Symbols: $1, Line: $<line>3}
 			}
 	;
 
@@ -540,6 +550,8 @@ declarations:		declaration
 
 declaration:		typeDeclaration
 			{
+				addType(&smilex, $1)
+				$$ = Node{}
 			}
 	|		valueDeclaration
 			{
@@ -614,6 +626,7 @@ choiceClause:		tCHOICE
 			/* the scanner skips until... */
 			'}'
 			{
+				$$ = Syntax{Type: "CHOICE"}
 			}
 	;
 
@@ -633,7 +646,7 @@ valueDeclaration:	fuzzy_lowercase_identifier
 			tOBJECT tIDENTIFIER
 			tCOLON_COLON_EQUAL '{' objectIdentifier '}'
 			{
-				$$ = Node{Label: $1, Type: NodeObjectID, IDs: $6}
+				$$ = Node{Label: $1, Type: NodeObjectID, IDs: $6, Line: $<line>1}
 			}
 	;
 
@@ -645,6 +658,9 @@ typeDeclaration:	typeName
 			}
 			tCOLON_COLON_EQUAL typeDeclarationRHS
 			{
+				$$ = $4
+				$$.Name = $1
+				$$.Line = $<line>1
 			}
 	;
 
@@ -683,6 +699,7 @@ typeSPPIonly:           tINTEGER64
 
 typeDeclarationRHS:	Syntax
 			{
+				$$ = Type{Syntax: $1}
 			}
 	|		tTEXTUAL_CONVENTION
 			{
@@ -695,15 +712,19 @@ typeDeclarationRHS:	Syntax
 			ReferPart
 			tSYNTAX Syntax
 			{
+				$$ = Type{Syntax: $11, IsTextualConvention: true, DisplayHint: $3, Status: $5,
+					Description: $7, Reference: $9}
 			}
 	|		choiceClause
 			{
+				$$ = Type{Syntax: $1}
 			}
 	;
 
 /* REF:RFC1902,7.1.12. */
 conceptualTable:	tSEQUENCE tOF row
 			{
+				$$ = Syntax{Type: "SEQUENCE OF"}
 			}
 	;
 
@@ -714,12 +735,14 @@ row:			tUPPERCASE_IDENTIFIER
 			 * module.
 			 */
 			{
+				$$ = Syntax{Type: $1}
 			}
 	;
 
 /* REF:RFC1902,7.1.12. */
 entryType:		tSEQUENCE '{' sequenceItems '}'
 			{
+				$$ = Syntax{Type: "SEQUENCE"}
 			}
 ;
 
@@ -744,9 +767,11 @@ sequenceItem:		tLOWERCASE_IDENTIFIER sequenceSyntax
 
 Syntax:			ObjectSyntax
 			{
+				$$ = $1
 			}
 	|		tBITS '{' NamedBits '}'
 			{
+				$$ = Syntax{Type: "BITS", Enums: $3}
 			}
 	;
 
@@ -764,9 +789,11 @@ sequenceSyntax:		/* ObjectSyntax */
 
 NamedBits:		NamedBit
 			{
+				$$ = []NamedNumber{$1}
 			}
 	|		NamedBits ',' NamedBit
 			{
+				$$ = append($1, $3)
 			}
 	;
 
@@ -775,6 +802,7 @@ NamedBit:		tLOWERCASE_IDENTIFIER
 			}
 			'(' tNUMBER ')'
 			{
+				$$ = NamedNumber{Name: $1, Value: int64($4)}
 			}
 	;
 
@@ -786,7 +814,8 @@ objectIdentityClause:	tLOWERCASE_IDENTIFIER
 			tCOLON_COLON_EQUAL
 			'{' objectIdentifier '}'
 			{
-				$$ = Node{Label: $1, Type: NodeObjectID, IDs: $10}
+				$$ = Node{Label: $1, Type: NodeObjectID, IDs: $10, Line: $<line>1, Status: $4,
+					Description: $6, Reference: $7}
 			}
 	;
 
@@ -807,15 +836,20 @@ objectTypeClause:	tLOWERCASE_IDENTIFIER
 			DefValPart                   /* old $14, new $19 */
 			tCOLON_COLON_EQUAL '{' ObjectName '}' /* old $17, new $22 */
 			{
-				$$ = Node{Label: $1, Type: NodeObjectType, IDs: $20}
+				syntax := $4
+				$$ = Node{Label: $1, Type: NodeObjectType, IDs: $20, Line: $<line>1,
+					Syntax: &syntax, Units: $5, Access: $6, Status: $10, Description: $11,
+					Reference: $13, Index: $15, Augments: $14}
 			}
 	;
 
 descriptionClause:	/* empty */
 			{
+				$$ = ""
 			}
 	|		tDESCRIPTION Text
 			{
+				$$ = $2
 			}
 	;
 
@@ -864,16 +898,22 @@ DescrPart:		tDESCRIPTION Text
 
 MaxOrPIBAccessPart:     MaxAccessPart
                         {
+				$$ = $1
                         }
         |               PibAccessPart
                         {
+				$$ = $1
                         }
         |               /* empty */
-                        { }
+                        {
+				$$ = ""
+			}
         ;
 
 PibAccessPart:          PibAccess Access
-                        { }
+                        {
+				$$ = $2
+			}
         ;
 
 PibAccess:              tPOLICY_ACCESS
@@ -956,12 +996,16 @@ MaxAccessPart:		tMAX_ACCESS
 			{
 			}
 			Access
-			{ }
+			{
+				$$ = $3
+			}
 	|		tACCESS
 			{
 			}
 			Access
-			{ }
+			{
+				$$ = $3
+			}
 	;
 
 notificationTypeClause:	tLOWERCASE_IDENTIFIER
@@ -973,7 +1017,8 @@ notificationTypeClause:	tLOWERCASE_IDENTIFIER
 			tCOLON_COLON_EQUAL
 			'{' NotificationName '}'
 			{
-				$$ = Node{Label: $1, Type: NodeNotification, IDs: $11}
+				$$ = Node{Label: $1, Type: NodeNotification, IDs: $11, Line: $<line>1, Objects: $3,
+					Status: $5, Description: $7, Reference: $8}
 			}
 	;
 
@@ -988,7 +1033,7 @@ moduleIdentityClause:	tLOWERCASE_IDENTIFIER
 			tCOLON_COLON_EQUAL
 			'{' objectIdentifier '}'
 			{
-				$$ = Node{Label: $1, Type: NodeModuleID, IDs: $15}
+				$$ = Node{Label: $1, Type: NodeModuleID, IDs: $15, Line: $<line>1, Description: $11}
 			}
         ;
 
@@ -1023,21 +1068,27 @@ CategoryID:		tLOWERCASE_IDENTIFIER
 
 ObjectSyntax:		SimpleSyntax
 			{
+				$$ = $1
 			}
 	|		typeTag SimpleSyntax
 			{
+				$$ = $2
 			}
 	|		conceptualTable
 			{
+				$$ = $1
 			}
 	|		row		     /* the uppercase name of a row  */
 			{
+				$$ = $1
 			}
 	|		entryType	     /* tSEQUENCE { ... } phrase */
 			{
+				$$ = $1
 			}
 	|		ApplicationSyntax
 			{
+				$$ = $1
 			}
         ;
 
@@ -1069,60 +1120,75 @@ valueofObjectSyntax:	valueofSimpleSyntax
 
 SimpleSyntax:		tINTEGER			/* (-2147483648..2147483647) */
 			{
+				$$ = Syntax{Type: "INTEGER"}
 			}
 	|		tINTEGER
 			{
 			}
 			integerSubType
 			{
+				$$ = Syntax{Type: "INTEGER", Ranges: $3}
 			}
 	|		tINTEGER
 			{
 			}
 			enumSpec
 			{
+				$$ = Syntax{Type: "INTEGER", Enums: $3}
 			}
 	|		tINTEGER32		/* (-2147483648..2147483647) */
 			{
+				$$ = Syntax{Type: "Integer32"}
 			}
         |		tINTEGER32
 			{
 			}
 			integerSubType
 			{
+				$$ = Syntax{Type: "Integer32", Ranges: $3}
 			}
 	|		tUPPERCASE_IDENTIFIER
 			{
 			}
 			enumSpec
 			{
+				$$ = Syntax{Type: $1, Enums: $3}
 			}
 	|		moduleName '.' tUPPERCASE_IDENTIFIER enumSpec
 			{
+				$$ = Syntax{Type: $3, Enums: $4}
 			}
 	|		tUPPERCASE_IDENTIFIER integerSubType
 			{
+				$$ = Syntax{Type: $1, Ranges: $2}
 			}
 	|		moduleName '.' tUPPERCASE_IDENTIFIER integerSubType
 			{
+				$$ = Syntax{Type: $3, Ranges: $4}
 			}
 	|		tOCTET tSTRING		/* (tSIZE (0..65535))	     */
 			{
+				$$ = Syntax{Type: "OCTET STRING"}
 			}
 	|		tOCTET tSTRING
 			{
 			}
 			octetStringSubType
 			{
+				$$ = Syntax{Type: "OCTET STRING", Sizes: $4}
 			}
 	|		tUPPERCASE_IDENTIFIER octetStringSubType
 			{
+				$$ = Syntax{Type: $1, Sizes: $2}
 			}
 	|		moduleName '.' tUPPERCASE_IDENTIFIER octetStringSubType
 			{
+				$$ = Syntax{Type: $3, Sizes: $4}
 			}
 	|		tOBJECT tIDENTIFIER anySubType
 			{
+				$$ = $3
+				$$.Type = "OBJECT IDENTIFIER"
 			}
         ;
 
@@ -1192,54 +1258,72 @@ sequenceSimpleSyntax:	tINTEGER	anySubType
 
 ApplicationSyntax:	tIPADDRESS anySubType
 			{
+				$$ = $2
+				$$.Type = "IpAddress"
 			}
 	|		tCOUNTER32  /* (0..4294967295)	     */
 			{
+				$$ = Syntax{Type: "Counter32"}
 			}
 	|		tCOUNTER32 integerSubType
 			{
+				$$ = Syntax{Type: "Counter32", Ranges: $2}
 			}
 	|		tGAUGE32			/* (0..4294967295)	     */
 			{
+				$$ = Syntax{Type: "Gauge32"}
 			}
 	|		tGAUGE32 integerSubType
 			{
+				$$ = Syntax{Type: "Gauge32", Ranges: $2}
 			}
 	|		tUNSIGNED32		/* (0..4294967295)	     */
 			{
+				$$ = Syntax{Type: "Unsigned32"}
 			}
 	|		tUNSIGNED32
 			{
 			}
 			integerSubType
 			{
+				$$ = Syntax{Type: "Unsigned32", Ranges: $3}
 			}
 	|		tTIMETICKS anySubType
 			{
+				$$ = $2
+				$$.Type = "TimeTicks"
 			}
 	|		tOPAQUE			/* IMPLICIT OCTET STRING     */
 			{
+				$$ = Syntax{Type: "Opaque"}
 			}
 	|		tOPAQUE octetStringSubType
 			{
+				$$ = Syntax{Type: "Opaque", Sizes: $2}
 			}
 	|		tCOUNTER64
 			{
+				$$ = Syntax{Type: "Counter64"}
 			}
 	|		tCOUNTER64 integerSubType
 			{
+				$$ = Syntax{Type: "Counter64", Ranges: $2}
 			}
 	|		tINTEGER64               /* (-9223372036854775807..9223372036854775807) */
 			{
+				$$ = Syntax{Type: "Integer64"}
 			}
 	|		tINTEGER64 integerSubType
 			{
+				$$ = Syntax{Type: "Integer64", Ranges: $2}
 			}
 	|		tUNSIGNED64	        /* (0..18446744073709551615) */
 			{
+				$$ = Syntax{Type: "Unsigned64"}
 			}
 	|		tUNSIGNED64 integerSubType
 			{
+				$$ = Syntax{Type: "Unsigned64", Ranges: $2}
 			}
 	;
 
@@ -1278,15 +1362,19 @@ sequenceApplicationSyntax: tIPADDRESS anySubType
 
 anySubType:		integerSubType
 			{
+				$$ = Syntax{Ranges: $1}
 			}
 	|	        octetStringSubType
 			{
+				$$ = Syntax{Sizes: $1}
 			}
 	|		enumSpec
 			{
+				$$ = Syntax{Enums: $1}
 			}
 	|		/* empty */
 			{
+				$$ = Syntax{}
 			}
         ;
 
@@ -1299,7 +1387,9 @@ integerSubType:		'(' ranges ')'		/* at least one range        */
 			 * conflicts. instead, we differentiate the parent
 			 * rule(s) (SimpleSyntax).
 			 */
-			{}
+			{
+				$$ = $2
+			}
 	;
 
 octetStringSubType:	'(' tSIZE '(' ranges ')' ')'
@@ -1310,55 +1400,69 @@ octetStringSubType:	'(' tSIZE '(' ranges ')' ')'
 			 * rule(s) (SimpleSyntax).
 			 */
 			{
+				$$ = $4
 			}
 	;
 
 ranges:			range
 			{
+				$$ = []Range{$1}
 			}
 	|		ranges '|' range
 			{
+				$$ = append($1, $3)
 			}
 	;
 
 range:			value
 			{
+				$$ = Range{Min: $1, Max: $1}
 			}
 	|		value tDOT_DOT value
 			{
+				$$ = Range{Min: $1, Max: $3}
 			}
 	;
 
 value:			tNEGATIVENUMBER
 			{
+				$$ = int64($1)
 			}
 	|		tNUMBER
 			{
+				$$ = int64($1)
 			}
 	|		tNEGATIVENUMBER64
 			{
+				$$ = $1
 			}
 	|		tNUMBER64
 			{
+				$$ = clampUint64($1)
 			}
 	|		tHEX_STRING
 			{
+				$$ = parseNumericString($1, 16)
 			}
 	|		tBIN_STRING
 			{
+				$$ = parseNumericString($1, 2)
 			}
 	;
 
 enumSpec:		'{' enumItems '}'
 			{
+				$$ = $2
 			}
 	;
 
 enumItems:		enumItem
 			{
+				$$ = []NamedNumber{$1}
 			}
 	|		enumItems ',' enumItem
 			{
+				$$ = append($1, $3)
 			}
 	;
 
@@ -1367,19 +1471,23 @@ enumItem:		tLOWERCASE_IDENTIFIER
 			}
 			'(' enumNumber ')'
 			{
+				$$ = NamedNumber{Name: $1, Value: $4}
 			}
 	;
 
 enumNumber:		tNUMBER
 			{
+				$$ = int64($1)
 			}
 	|		tNEGATIVENUMBER
 			{
+				$$ = int64($1)
 			}
 	;
 
 Status:			tLOWERCASE_IDENTIFIER
 			{
+				$$ = $1
 			}
         ;
 
@@ -1390,22 +1498,27 @@ Status_Capabilities:	tLOWERCASE_IDENTIFIER
 
 DisplayPart:		tDISPLAY_HINT Text
 			{
+				$$ = $2
 			}
         |		/* empty */
 			{
+				$$ = ""
 			}
         ;
 
 UnitsPart:		tUNITS Text
 			{
+				$$ = $2
 			}
         |		/* empty */
 			{
+				$$ = ""
 			}
         ;
 
 Access:			tLOWERCASE_IDENTIFIER
 			{
+				$$ = $1
 			}
         ;
 
@@ -1414,18 +1527,22 @@ IndexPart:              tPIB_INDEX
                         }
                         '{' Entry '}'
                         {
+				$$ = ""
 			}
         |		tAUGMENTS '{' Entry '}'
 			{
+				$$ = $3
 			}
         |		tEXTENDS
                         {
                         }
                         '{' Entry '}'
 			{
+				$$ = ""
 			}
         |		/* empty */
 			{
+				$$ = ""
 			}
 	;
 
@@ -1434,35 +1551,43 @@ MibIndex:		tINDEX
 			}
 			'{' IndexTypes '}'
 			{
+				$$ = $4
                         }
         |               /* empty */
 			{
+				$$ = nil
 			}
         ;
 
 IndexTypes:		IndexType
 			{
+				$$ = []IndexObject{$1}
 			}
         |		IndexTypes ',' IndexType
 			{
+				$$ = append($1, $3)
 			}
 	;
 
 IndexType:		tIMPLIED Index
 			{
+				$$ = IndexObject{Name: $2, Implied: true}
 			}
 	|		Index
 			{
+				$$ = IndexObject{Name: $1}
 			}
 	;
 
 Index:			ObjectName
 			{
+				$$ = $1[len($1)-1].Label
 			}
         ;
 
 Entry:			ObjectName
 			{
+				$$ = $1[len($1)-1].Label
 			}
         ;
 
@@ -1506,9 +1631,12 @@ NotificationName:	objectIdentifier
 
 ReferPart:		tREFERENCE Text
 			{
+				$$ = $2
 			}
 	|		/* empty */
-			{}
+			{
+				$$ = ""
+			}
 	;
 
 RevisionPart:		Revisions
@@ -1533,27 +1661,33 @@ Revision:		tREVISION ExtUTCTime
 
 NotificationObjectsPart: tOBJECTS '{' Objects '}'
 			{
+				$$ = $3
 			}
 	|		/* empty */
 			{
+				$$ = nil
 			}
 	;
 
 ObjectGroupObjectsPart:	tOBJECTS '{' Objects '}'
 			{
+				$$ = $3
 			}
 	;
 
 Objects:		Object
 			{
+				$$ = []string{$1}
 			}
 	|		Objects ',' Object
 			{
+				$$ = append($1, $3)
 			}
 	;
 
 Object:			ObjectName
 			{
+				$$ = $1[len($1)-1].Label
 			}
 	;
 
@@ -1577,6 +1711,7 @@ Notification:		NotificationName
 
 Text:			tQUOTED_STRING
 			{
+				$$ = $1
 			}
 	;
 
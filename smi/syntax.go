@@ -0,0 +1,202 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi
+
+import (
+	"math"
+	"strconv"
+)
+
+// A Syntax describes the SYNTAX clause of an OBJECT-TYPE or the type in a
+// type definition. Type is either an SMI base type, such as INTEGER, OCTET
+// STRING or Counter32, or the name of a type defined by a module. Ranges
+// holds the value ranges of an integer type, Sizes holds the SIZE ranges of
+// a string type and Enums holds the named numbers of an enumerated INTEGER
+// or the named bits of BITS.
+type Syntax struct {
+	Type   string
+	Ranges []Range
+	Sizes  []Range
+	Enums  []NamedNumber
+}
+
+// A Range is a range of values or sizes in a Syntax. A single value is
+// represented by a Range with equal Min and Max.
+type Range struct {
+	Min int64
+	Max int64
+}
+
+// A NamedNumber is an enumerated value or a named bit.
+type NamedNumber struct {
+	Name  string
+	Value int64
+}
+
+// A Type represents a type assignment or TEXTUAL-CONVENTION in a module.
+// The Status, Description and Reference fields are only set for textual
+// conventions.
+type Type struct {
+	Name                string
+	Syntax              Syntax
+	IsTextualConvention bool
+	DisplayHint         string
+	Status              string
+	Description         string
+	Reference           string
+	Line                int
+}
+
+// An IndexObject is an object named in the INDEX clause of a table row.
+type IndexObject struct {
+	Name    string
+	Implied bool
+}
+
+// smiBaseTypes are the types that are not defined in terms of other types.
+var smiBaseTypes = map[string]bool{
+	"INTEGER":           true,
+	"Integer32":         true,
+	"Unsigned32":        true,
+	"Counter32":         true,
+	"Gauge32":           true,
+	"TimeTicks":         true,
+	"Counter64":         true,
+	"Integer64":         true,
+	"Unsigned64":        true,
+	"OCTET STRING":      true,
+	"Opaque":            true,
+	"IpAddress":         true,
+	"OBJECT IDENTIFIER": true,
+	"BITS":              true,
+	"SEQUENCE":          true,
+	"SEQUENCE OF":       true,
+	"CHOICE":            true,
+}
+
+// MaxTypeDepth limits how many type definitions, or AUGMENTS clauses, are
+// followed in a chain, so that definitions that refer to each other cannot
+// cause an endless loop.
+const MaxTypeDepth = 16
+
+// resolveSyntax follows the type definitions starting with syntax, as used in
+// module mod, to the SMI base type it is derived from. The ranges, sizes and
+// enumerations are taken from the most refined type in the chain that has them.
+// The second result is false if a type in the chain is not defined.
+func (mib *MIB) resolveSyntax(mod *Module, syntax Syntax) (Syntax, bool) {
+	resolved := syntax
+	for depth := 0; !smiBaseTypes[syntax.Type]; depth++ {
+		if depth == MaxTypeDepth {
+			return resolved, false
+		}
+		var t *Type
+		mod, t = mib.findType(mod, syntax.Type)
+		if t == nil {
+			return resolved, false
+		}
+		syntax = t.Syntax
+		if resolved.Ranges == nil {
+			resolved.Ranges = syntax.Ranges
+		}
+		if resolved.Sizes == nil {
+			resolved.Sizes = syntax.Sizes
+		}
+		if resolved.Enums == nil {
+			resolved.Enums = syntax.Enums
+		}
+	}
+	resolved.Type = syntax.Type
+	return resolved, true
+}
+
+// FindType returns the definition of the type name as seen from module mod,
+// along with the module that defines it. Imported types are found in the
+// module they are imported from. It returns nil for the SMI base types and
+// for types that are not defined by a loaded module.
+func (mib *MIB) FindType(mod *Module, name string) (*Module, *Type) {
+	return mib.findType(mod, name)
+}
+
+// findType returns the definition of the type name as seen from module mod,
+// along with the module that defines it.
+func (mib *MIB) findType(mod *Module, name string) (*Module, *Type) {
+	for i := range mod.Types {
+		if mod.Types[i].Name == name {
+			return mod, &mod.Types[i]
+		}
+	}
+	for _, imp := range mod.Imports {
+		for _, impName := range imp.Symbols {
+			if name == impName {
+				impMod := mib.Modules[mib.moduleName(imp.From)]
+				if impMod == nil || !impMod.IsLoaded {
+					return nil, nil
+				}
+				return mib.findType(impMod, name)
+			}
+		}
+	}
+	// SMIv1 modules sometimes use types without importing them
+	for _, modName := range mib.loadOrder {
+		other := mib.Modules[modName]
+		for i := range other.Types {
+			if other.Types[i].Name == name {
+				return other, &other.Types[i]
+			}
+		}
+	}
+	return nil, nil
+}
+
+// LookupSymbol returns the symbol label as seen from module mod, such as an
+// object named in an INDEX or AUGMENTS clause. Imported symbols are found in
+// the module they are imported from. It returns nil if there is no symbol
+// with that label.
+func (mib *MIB) LookupSymbol(mod *Module, label string) *Symbol {
+	return mib.lookupSymbol(mod, label)
+}
+
+// lookupSymbol finds the symbol label as seen from module mod, like
+// findSymbol, but without reporting diagnostics.
+func (mib *MIB) lookupSymbol(mod *Module, label string) *Symbol {
+	if sym, ok := mod.Symbols[label]; ok {
+		return sym
+	}
+	for _, imp := range mod.Imports {
+		for _, impLabel := range imp.Symbols {
+			if label == impLabel {
+				impMod := mib.Modules[mib.moduleName(imp.From)]
+				if impMod == nil || !impMod.IsLoaded {
+					return nil
+				}
+				return mib.lookupSymbol(impMod, label)
+			}
+		}
+	}
+	return mib.Symbols[label]
+}
+
+// clampUint64 converts a number to int64, limiting it to the largest int64.
+// Only the largest Unsigned64 and Counter64 range bounds are affected.
+func clampUint64(n uint64) int64 {
+	if n > math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return int64(n)
+}
+
+// parseNumericString returns the value of a hexadecimal or binary string
+// used as a number, such as 'FF'H or '0101'B.
+func parseNumericString(s string, base int) int64 {
+	if s == "" {
+		return 0
+	}
+	n, err := strconv.ParseUint(s, base, 64)
+	if err != nil {
+		return math.MaxInt64
+	}
+	return clampUint64(n)
+}
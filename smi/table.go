@@ -0,0 +1,127 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi
+
+import (
+	"sort"
+)
+
+// A Table is a conceptual table reconstructed from the varbinds of its
+// column instances. Symbol and Row are the table and row objects that define
+// the INDEX, and Index holds the INDEX objects. Columns are the columns that
+// have values, those of the row first and then those of rows that AUGMENT
+// it, each in order by OID. Rows are in order by index.
+type Table struct {
+	Symbol  *Symbol
+	Row     *Symbol
+	Index   []*Symbol
+	Columns []*Symbol
+	Rows    []*TableRow
+}
+
+// A TableRow is a row of a Table. Key holds the values of the INDEX objects
+// decoded from the Index as FormatOID renders them, or the Index in dotted
+// form if it cannot be decoded. Values and Rendered hold the value of each
+// column by name, Rendered as RenderVarbinds shows it. Columns without a
+// value in the row are missing from the maps.
+type TableRow struct {
+	Index    OID
+	Key      []string
+	Values   map[string]interface{}
+	Rendered map[string]string
+}
+
+// Tables groups the varbinds of table column instances by table and row.
+// The columns of a row that AUGMENTS another row are merged into the rows of
+// the table they augment. Varbinds of other objects and with an empty OID
+// are ignored. If a
+// varbind occurs more than once, the last one is used. The tables are
+// returned in order by OID.
+func (mib *MIB) Tables(varbinds []Varbind) []*Table {
+	rendered := mib.RenderVarbinds(varbinds)
+	tables := make(map[*Symbol]*Table)
+	rows := make(map[*Table]map[string]*TableRow)
+	columns := make(map[*Table]map[*Symbol]bool)
+	for i, vb := range varbinds {
+		if len(vb.OID) == 0 {
+			continue
+		}
+		sym, idx := mib.Symbol(vb.OID)
+		if sym == nil || !sym.IsColumn() || len(idx) == 0 {
+			continue
+		}
+		base := mib.baseRow(sym.Parent)
+		t := tables[base]
+		if t == nil {
+			t = &Table{Symbol: base.Parent, Row: base}
+			for _, part := range mib.rowIndex(base) {
+				t.Index = append(t.Index, part.object)
+			}
+			tables[base] = t
+			rows[t] = make(map[string]*TableRow)
+			columns[t] = make(map[*Symbol]bool)
+		}
+		row := rows[t][idx.String()]
+		if row == nil {
+			row = &TableRow{
+				Index:    idx,
+				Values:   make(map[string]interface{}),
+				Rendered: make(map[string]string),
+			}
+			if key, ok := mib.decodeIndex(base, idx, false); ok {
+				row.Key = key
+			} else {
+				row.Key = []string{idx.String()}
+			}
+			rows[t][idx.String()] = row
+			t.Rows = append(t.Rows, row)
+		}
+		row.Values[sym.Name] = vb.Value
+		row.Rendered[sym.Name] = rendered[i].Value
+		if !columns[t][sym] {
+			columns[t][sym] = true
+			t.Columns = append(t.Columns, sym)
+		}
+	}
+
+	result := make([]*Table, 0, len(tables))
+	oids := make(map[*Symbol]OID)
+	for _, t := range tables {
+		oids[t.Symbol] = mib.symbolOID(t.Symbol)
+		for _, column := range t.Columns {
+			oids[column] = mib.symbolOID(column)
+		}
+		sort.Slice(t.Columns, func(i, j int) bool {
+			a, b := t.Columns[i], t.Columns[j]
+			if (a.Parent == t.Row) != (b.Parent == t.Row) {
+				return a.Parent == t.Row
+			}
+			return oids[a].Compare(oids[b]) < 0
+		})
+		sort.Slice(t.Rows, func(i, j int) bool {
+			return t.Rows[i].Index.Compare(t.Rows[j].Index) < 0
+		})
+		result = append(result, t)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return oids[result[i].Symbol].Compare(oids[result[j].Symbol]) < 0
+	})
+	return result
+}
+
+// baseRow follows the AUGMENTS clauses of the table row to the row that
+// defines its INDEX. It returns row itself if the augmented row is not found.
+func (mib *MIB) baseRow(row *Symbol) *Symbol {
+	base := row
+	for depth := 0; base.Node != nil && base.Node.Augments != ""; depth++ {
+		next := mib.lookupSymbol(base.Module, base.Node.Augments)
+		if next == nil || depth == MaxTypeDepth {
+			return row
+		}
+		base = next
+	}
+	return base
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hallidave/mibtool/smi"
+)
+
+func TestTables(t *testing.T) {
+	mib := smi.NewMIB("testdata")
+	err := mib.LoadModules("IF-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	walk, err := mib.ParseWalk(strings.NewReader(`SNMPv2-MIB::sysDescr.0 = STRING: router
+IF-MIB::ifName.2 = STRING: eth1
+IF-MIB::ifDescr.10 = STRING: lo
+IF-MIB::ifDescr.2 = STRING: eth1
+IF-MIB::ifOperStatus.2 = INTEGER: down(2)
+IF-MIB::ifName.10 = STRING: lo
+IF-MIB::ifStackStatus.0.2 = INTEGER: active(1)
+IF-MIB::ifOperStatus.10 = INTEGER: up(1)
+IF-MIB::ifStackStatus.2.10 = INTEGER: active(1)
+IF-MIB::ifDescr.2 = STRING: eth1.0
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	varbinds := make([]smi.Varbind, len(walk))
+	for i, vb := range walk {
+		varbinds[i] = vb.Varbind
+	}
+	varbinds = append(varbinds, smi.Varbind{OID: nil, Value: 1})
+
+	var lines []string
+	for _, table := range mib.Tables(varbinds) {
+		var names []string
+		for _, column := range table.Columns {
+			names = append(names, column.Name)
+		}
+		var index []string
+		for _, sym := range table.Index {
+			index = append(index, sym.Name)
+		}
+		lines = append(lines, fmt.Sprintf("%s %v %v", table.Symbol, index, names))
+		for _, row := range table.Rows {
+			var values []string
+			for _, name := range names {
+				values = append(values, row.Rendered[name])
+			}
+			lines = append(lines, fmt.Sprintf("  %v %v", row.Key, values))
+		}
+	}
+	expected := []string{
+		"IF-MIB::ifTable [ifIndex] [ifDescr ifOperStatus ifName]",
+		"  [2] [eth1.0 down(2) eth1]",
+		"  [10] [lo up(1) lo]",
+		"IF-MIB::ifStackTable [ifStackHigherLayer ifStackLowerLayer] [ifStackStatus]",
+		"  [0 2] [active(1)]",
+		"  [2 10] [active(1)]",
+	}
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("got\n%s\nexpected\n%s", strings.Join(lines, "\n"), strings.Join(expected, "\n"))
+	}
+
+	tables := mib.Tables(varbinds)
+	if v := tables[0].Rows[1].Values["ifOperStatus"]; v != int64(1) {
+		t.Errorf("got ifOperStatus value %#v, expected 1", v)
+	}
+	if tables[0].Row.Name != "ifEntry" {
+		t.Errorf("got row %s, expected ifEntry", tables[0].Row)
+	}
+}
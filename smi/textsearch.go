@@ -0,0 +1,297 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// TextField identifies the clause of a definition that a text search matched.
+type TextField int
+
+// TextField values
+const (
+	FieldDescription TextField = iota // the DESCRIPTION clause
+	FieldReference                    // the REFERENCE clause
+	FieldUnits                        // the UNITS clause
+)
+
+var textFieldNames = map[TextField]string{
+	FieldDescription: "DESCRIPTION",
+	FieldReference:   "REFERENCE",
+	FieldUnits:       "UNITS",
+}
+
+func (f TextField) String() string {
+	if name, ok := textFieldNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("TextField(%d)", int(f))
+}
+
+// A TextMatch is a definition found by SearchText. Either Symbol or Type is
+// set, depending on whether the text belongs to a symbol or to a textual
+// convention of Module. Snippet is the part of the text around the first
+// match, with runs of white space replaced by single spaces.
+type TextMatch struct {
+	Module  *Module
+	Symbol  *Symbol
+	Type    *Type
+	Field   TextField
+	Snippet string
+}
+
+// Name returns the Module::name string of the matched symbol or type.
+func (m *TextMatch) Name() string {
+	if m.Symbol != nil {
+		return m.Symbol.String()
+	}
+	return m.Module.Name + "::" + m.Type.Name
+}
+
+// textDoc is a piece of text in the index. The text is stored with its
+// white space collapsed, which is the form used for snippets.
+type textDoc struct {
+	module *Module
+	symbol *Symbol
+	typ    *Type
+	field  TextField
+	text   string
+}
+
+// A posting records that a word occurs in a document at a word position.
+type posting struct {
+	doc int32
+	pos int32
+}
+
+// textIndex is an inverted index from words to their occurrences in the
+// DESCRIPTION, REFERENCE and UNITS text of the loaded modules.
+type textIndex struct {
+	docs     []textDoc
+	postings map[string][]posting
+}
+
+// snippetContext is the number of bytes of text shown on either side of
+// the first match in a snippet.
+const snippetContext = 40
+
+// SearchText finds the symbols and textual conventions of the loaded
+// modules whose DESCRIPTION, REFERENCE or UNITS text contains all the words
+// of query. Words are runs of letters and digits and are compared ignoring
+// case. Text containing the words as a phrase is ranked first, then text
+// with more occurrences of the words. The index is built by the first
+// search after modules are loaded or unloaded. Searches may run
+// concurrently with each other, but not with loading modules. Limit is the
+// maximum number of results, or 0 for no limit.
+func (mib *MIB) SearchText(query string, limit int) []TextMatch {
+	var words []string
+	for _, word := range textWords(query) {
+		words = append(words, word.text)
+	}
+	if len(words) == 0 {
+		return nil
+	}
+	mib.textMu.Lock()
+	if mib.textIndex == nil {
+		mib.textIndex = mib.buildTextIndex()
+	}
+	index := mib.textIndex
+	mib.textMu.Unlock()
+
+	// Count the query words found in each document, keeping the documents
+	// with all of them, then collect the positions of the words in those.
+	counts := make(map[int32]int)
+	for i, word := range words {
+		for _, p := range index.postings[word] {
+			if counts[p.doc] == i {
+				counts[p.doc] = i + 1
+			}
+		}
+	}
+	positions := make(map[int32][][]int32)
+	for doc, n := range counts {
+		if n == len(words) {
+			positions[doc] = make([][]int32, len(words))
+		}
+	}
+	for i, word := range words {
+		for _, p := range index.postings[word] {
+			if pos, ok := positions[p.doc]; ok {
+				pos[i] = append(pos[i], p.pos)
+			}
+		}
+	}
+
+	type hit struct {
+		doc    int32
+		phrase bool
+		count  int
+		start  int32
+	}
+	hits := make([]hit, 0, len(positions))
+	for doc, pos := range positions {
+		h := hit{doc: doc}
+		for _, p := range pos {
+			h.count += len(p)
+		}
+		h.start, h.phrase = findPhrase(pos)
+		hits = append(hits, h)
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		a, b := hits[i], hits[j]
+		if a.phrase != b.phrase {
+			return a.phrase
+		}
+		if a.count != b.count {
+			return a.count > b.count
+		}
+		return a.doc < b.doc
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	matches := make([]TextMatch, len(hits))
+	for i, h := range hits {
+		doc := &index.docs[h.doc]
+		matches[i] = TextMatch{
+			Module:  doc.module,
+			Symbol:  doc.symbol,
+			Type:    doc.typ,
+			Field:   doc.field,
+			Snippet: snippet(doc.text, h.start),
+		}
+	}
+	return matches
+}
+
+// findPhrase returns the first word position at which the query words occur
+// as a phrase, given the sorted positions of each query word in a document.
+// If they do not occur as a phrase the first position of the first word is
+// returned.
+func findPhrase(positions [][]int32) (int32, bool) {
+	for _, start := range positions[0] {
+		phrase := true
+		for i, pos := range positions[1:] {
+			if _, found := slices.BinarySearch(pos, start+int32(i)+1); !found {
+				phrase = false
+				break
+			}
+		}
+		if phrase {
+			return start, true
+		}
+	}
+	return positions[0][0], false
+}
+
+// buildTextIndex indexes the text of the loaded modules in load order,
+// with the symbols of each module in order by OID followed by its types.
+func (mib *MIB) buildTextIndex() *textIndex {
+	index := &textIndex{postings: make(map[string][]posting)}
+	for _, modName := range mib.loadOrder {
+		mod := mib.Modules[modName]
+		syms := make([]*Symbol, 0, len(mod.Symbols))
+		oids := make(map[*Symbol]OID, len(mod.Symbols))
+		for _, sym := range mod.Symbols {
+			syms = append(syms, sym)
+			oids[sym] = mib.symbolOID(sym)
+		}
+		sort.Slice(syms, func(i, j int) bool {
+			return oids[syms[i]].Compare(oids[syms[j]]) < 0
+		})
+		for _, sym := range syms {
+			if sym.Node == nil {
+				continue
+			}
+			index.add(textDoc{module: mod, symbol: sym, field: FieldDescription}, sym.Node.Description)
+			index.add(textDoc{module: mod, symbol: sym, field: FieldReference}, sym.Node.Reference)
+			index.add(textDoc{module: mod, symbol: sym, field: FieldUnits}, sym.Node.Units)
+		}
+		for i := range mod.Types {
+			t := &mod.Types[i]
+			index.add(textDoc{module: mod, typ: t, field: FieldDescription}, t.Description)
+			index.add(textDoc{module: mod, typ: t, field: FieldReference}, t.Reference)
+		}
+	}
+	return index
+}
+
+func (index *textIndex) add(doc textDoc, text string) {
+	doc.text = strings.Join(strings.Fields(text), " ")
+	words := textWords(doc.text)
+	if len(words) == 0 {
+		return
+	}
+	id := int32(len(index.docs))
+	index.docs = append(index.docs, doc)
+	for pos, word := range words {
+		index.postings[word.text] = append(index.postings[word.text], posting{doc: id, pos: int32(pos)})
+	}
+}
+
+// A textWord is a lower case word and its byte offset in the text.
+type textWord struct {
+	text   string
+	offset int
+}
+
+func textWords(s string) []textWord {
+	var words []textWord
+	start := -1
+	for i, r := range s {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWord && start < 0 {
+			start = i
+		} else if !isWord && start >= 0 {
+			words = append(words, textWord{strings.ToLower(s[start:i]), start})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, textWord{strings.ToLower(s[start:]), start})
+	}
+	return words
+}
+
+// snippet returns the part of text around the word at position pos,
+// cut at word boundaries and marked with ellipses where it is shortened.
+func snippet(text string, pos int32) string {
+	words := textWords(text)
+	if pos < 0 || int(pos) >= len(words) {
+		pos = 0
+	}
+	offset := words[pos].offset
+	start := 0
+	if offset > snippetContext {
+		start = offset - snippetContext
+		if i := strings.IndexByte(text[start:offset], ' '); i >= 0 {
+			start += i + 1
+		} else {
+			start = offset
+		}
+	}
+	end := len(text)
+	if end-offset > 2*snippetContext {
+		end = offset + 2*snippetContext
+		if i := strings.LastIndexByte(text[offset:end], ' '); i > 0 {
+			end = offset + i
+		}
+	}
+	s := text[start:end]
+	if start > 0 {
+		s = "..." + s
+	}
+	if end < len(text) {
+		s += "..."
+	}
+	return s
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/hallidave/mibtool/smi"
+)
+
+func TestSearchText(t *testing.T) {
+	mib := smi.NewMIB("testdata")
+	err := mib.LoadModules("IF-MIB", "RMON-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query    string
+		limit    int
+		expected []string
+	}{
+		{"CRC errors", 0, []string{
+			"RMON-MIB::filterPktStatus DESCRIPTION",
+			"RMON-MIB::captureBufferPacketStatus DESCRIPTION",
+		}},
+		{"rfc 1906", 0, []string{
+			"SNMPv2-TC::TDomain REFERENCE",
+			"SNMPv2-TC::TAddress REFERENCE",
+		}},
+		{"milliseconds", 0, []string{
+			"RMON-MIB::captureBufferPacketTime DESCRIPTION",
+			"RMON-MIB::captureBufferPacketTime UNITS",
+		}},
+		{"counter discontinuities", 2, []string{
+			"IF-MIB::ifInUnknownProtos DESCRIPTION",
+			"IF-MIB::ifInOctets DESCRIPTION",
+		}},
+		{"frobnicated", 0, []string{}},
+		{"  ", 0, []string{}},
+	}
+	for _, test := range tests {
+		matches := mib.SearchText(test.query, test.limit)
+		names := make([]string, len(matches))
+		for i, m := range matches {
+			names[i] = m.Name() + " " + m.Field.String()
+		}
+		if len(names) != len(test.expected) {
+			t.Errorf("%q: got %v, expected %v", test.query, names, test.expected)
+			continue
+		}
+		for i, name := range names {
+			if name != test.expected[i] {
+				t.Errorf("%q: got %v, expected %v", test.query, names, test.expected)
+				break
+			}
+		}
+	}
+
+	matches := mib.SearchText("CRC errors", 1)
+	expected := "...than 64 octets 2 Packet experienced a CRC or Alignment error For example, an Ethernet fragment would have a value of..."
+	if len(matches) != 1 || matches[0].Snippet != expected {
+		t.Errorf("got %+v, expected snippet %q", matches, expected)
+	}
+
+	if _, err := mib.UnloadModules("RMON-MIB"); err != nil {
+		t.Fatal(err)
+	}
+	if matches := mib.SearchText("CRC errors", 0); len(matches) != 0 {
+		t.Errorf("got %d matches after unloading RMON-MIB, expected none", len(matches))
+	}
+}
+
+func TestSearchTextConcurrent(t *testing.T) {
+	mib := smi.NewMIB("testdata")
+	err := mib.LoadModules("IF-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	results := make([]int, 4)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = len(mib.SearchText("counter discontinuities", 0))
+		}()
+	}
+	wg.Wait()
+	for i, n := range results {
+		if n == 0 || n != results[0] {
+			t.Errorf("search %d: got %d results, expected %d", i, n, results[0])
+		}
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi
+
+import (
+	"iter"
+	"sort"
+)
+
+// A SymbolFilter selects the symbols considered by the tree navigation
+// functions. A nil SymbolFilter selects every named symbol.
+type SymbolFilter func(sym *Symbol) bool
+
+// LeafObjects is a SymbolFilter that selects scalar and columnar objects,
+// the symbols that have instances in an SNMP agent.
+func LeafObjects(sym *Symbol) bool {
+	return sym.IsScalar() || sym.IsColumn()
+}
+
+func (f SymbolFilter) match(sym *Symbol) bool {
+	return sym.Name != "" && (f == nil || f(sym))
+}
+
+// Next returns the first symbol selected by filter whose OID comes after oid
+// in lexicographic order, as an SNMP GETNEXT request would. The OID of the
+// symbol is also returned. If there is no such symbol, Next returns nil.
+func (mib *MIB) Next(oid OID, filter SymbolFilter) (*Symbol, OID) {
+	return nextSymbol(mib.Root, OID{mib.Root.ID}, oid, filter)
+}
+
+func nextSymbol(sym *Symbol, symOID OID, oid OID, filter SymbolFilter) (*Symbol, OID) {
+	if symOID.Compare(oid) > 0 && filter.match(sym) {
+		return sym, symOID
+	}
+	for _, id := range sortedChildIDs(sym) {
+		childOID := symOID.Append(id)
+		// Skip subtrees that come entirely before oid
+		if childOID.Compare(oid) < 0 && !oid.HasPrefix(childOID) {
+			continue
+		}
+		if next, nextOID := nextSymbol(sym.ChildByID[id], childOID, oid, filter); next != nil {
+			return next, nextOID
+		}
+	}
+	return nil, nil
+}
+
+// Prev returns the last symbol selected by filter whose OID comes before oid
+// in lexicographic order, along with the OID of the symbol. If there is no
+// such symbol, Prev returns nil.
+func (mib *MIB) Prev(oid OID, filter SymbolFilter) (*Symbol, OID) {
+	return prevSymbol(mib.Root, OID{mib.Root.ID}, oid, filter)
+}
+
+func prevSymbol(sym *Symbol, symOID OID, oid OID, filter SymbolFilter) (*Symbol, OID) {
+	if symOID.Compare(oid) >= 0 {
+		// The symbol and its whole subtree come at or after oid
+		return nil, nil
+	}
+	ids := sortedChildIDs(sym)
+	for i := len(ids) - 1; i >= 0; i-- {
+		id := ids[i]
+		if prev, prevOID := prevSymbol(sym.ChildByID[id], symOID.Append(id), oid, filter); prev != nil {
+			return prev, prevOID
+		}
+	}
+	if filter.match(sym) {
+		return sym, symOID
+	}
+	return nil, nil
+}
+
+// FirstIn returns the first symbol selected by filter in the subtree below
+// oid, along with the OID of the symbol. The symbol for oid itself is not
+// part of the subtree. If there is no such symbol, FirstIn returns nil.
+func (mib *MIB) FirstIn(oid OID, filter SymbolFilter) (*Symbol, OID) {
+	sym, symOID := mib.Next(oid, filter)
+	if sym == nil || !symOID.IsDescendantOf(oid) {
+		return nil, nil
+	}
+	return sym, symOID
+}
+
+// WalkAction tells a walk how to continue after visiting a symbol.
+type WalkAction int
+
+// WalkAction values returned by a WalkFunc
+const (
+	WalkContinue WalkAction = iota // visit the children of the symbol next
+	WalkSkip                       // skip the children of the symbol
+	WalkStop                       // end the walk
+)
+
+// A WalkFunc is called for each symbol visited by a walk, with the symbol and its OID.
+type WalkFunc func(sym *Symbol, oid OID) WalkAction
+
+// Walk walks the subtree of symbols rooted at oid in order by OID, calling fn
+// for each symbol, starting with the symbol for oid itself. The return value
+// of fn controls whether the children of a symbol are visited and whether the
+// walk continues. Nothing is visited if oid does not identify a symbol.
+func (mib *MIB) Walk(oid OID, fn WalkFunc) {
+	if len(oid) == 0 {
+		return
+	}
+	sym, idx := mib.Symbol(oid)
+	if sym == nil || len(idx) > 0 {
+		return
+	}
+	walkSymbols(sym, append(OID{}, oid...), fn)
+}
+
+// WalkSymbol is like Walk, but starts the walk at the given symbol.
+func (mib *MIB) WalkSymbol(sym *Symbol, fn WalkFunc) {
+	mib.Walk(mib.symbolOID(sym), fn)
+}
+
+// All returns an iterator over all symbols in the MIB and their OIDs in order
+// by OID. It visits the same symbols as VisitSymbols.
+func (mib *MIB) All() iter.Seq2[*Symbol, OID] {
+	return func(yield func(*Symbol, OID) bool) {
+		walkChildren(mib.Root, OID{mib.Root.ID}, yieldWalk(yield))
+	}
+}
+
+// Subtree returns an iterator over the symbols in the subtree rooted at oid
+// and their OIDs in order by OID, starting with the symbol for oid itself.
+func (mib *MIB) Subtree(oid OID) iter.Seq2[*Symbol, OID] {
+	return func(yield func(*Symbol, OID) bool) {
+		mib.Walk(oid, yieldWalk(yield))
+	}
+}
+
+func yieldWalk(yield func(*Symbol, OID) bool) WalkFunc {
+	return func(sym *Symbol, oid OID) WalkAction {
+		if !yield(sym, oid) {
+			return WalkStop
+		}
+		return WalkContinue
+	}
+}
+
+// walkSymbols visits sym and its subtree, returning false if the walk was stopped.
+func walkSymbols(sym *Symbol, oid OID, fn WalkFunc) bool {
+	switch fn(sym, oid) {
+	case WalkStop:
+		return false
+	case WalkSkip:
+		return true
+	}
+	return walkChildren(sym, oid, fn)
+}
+
+// walkChildren visits the subtrees of the children of sym, returning false if
+// the walk was stopped.
+func walkChildren(sym *Symbol, oid OID, fn WalkFunc) bool {
+	for _, id := range sortedChildIDs(sym) {
+		if !walkSymbols(sym.ChildByID[id], oid.Append(id), fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// Children returns the children of the symbol in order by ID.
+func (s *Symbol) Children() []*Symbol {
+	ids := sortedChildIDs(s)
+	kids := make([]*Symbol, len(ids))
+	for i, id := range ids {
+		kids[i] = s.ChildByID[id]
+	}
+	return kids
+}
+
+// sortedChildIDs returns the IDs of the children of sym in order. The IDs
+// sorted when the tree was built are used unless ChildByID has changed size
+// since then, in which case they are sorted again from the map.
+func sortedChildIDs(sym *Symbol) []int {
+	if len(sym.childIDs) == len(sym.ChildByID) {
+		return sym.childIDs
+	}
+	ids := make([]int, 0, len(sym.ChildByID))
+	for id := range sym.ChildByID {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
@@ -0,0 +1,260 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hallidave/mibtool/smi"
+)
+
+func TestSymbolKinds(t *testing.T) {
+	mib := smi.NewMIB("testdata")
+	err := mib.LoadModules("IF-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name                       string
+		scalar, table, row, column bool
+	}{
+		{"interfaces", false, false, false, false},
+		{"ifNumber", true, false, false, false},
+		{"ifTable", false, true, false, false},
+		{"ifEntry", false, false, true, false},
+		{"ifIndex", false, false, false, true},
+		{"linkDown", false, false, false, false},
+	}
+	for _, test := range tests {
+		sym := mib.Symbols[test.name]
+		if sym.IsScalar() != test.scalar || sym.IsTable() != test.table ||
+			sym.IsRow() != test.row || sym.IsColumn() != test.column {
+			t.Errorf("%s: unexpected kind: scalar %v, table %v, row %v, column %v", test.name,
+				sym.IsScalar(), sym.IsTable(), sym.IsRow(), sym.IsColumn())
+		}
+	}
+}
+
+func TestNextPrev(t *testing.T) {
+	mib := smi.NewMIB("testdata")
+	err := mib.LoadModules("IF-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaves := smi.SymbolFilter(smi.LeafObjects)
+	tests := []struct {
+		from   string
+		filter smi.SymbolFilter
+		next   string
+		prev   string
+	}{
+		{"IF-MIB::ifIndex", nil, "IF-MIB::ifDescr", "IF-MIB::ifEntry"},
+		{"IF-MIB::ifIndex", leaves, "IF-MIB::ifDescr", "IF-MIB::ifNumber"},
+		{"IF-MIB::ifDescr.3", leaves, "IF-MIB::ifType", "IF-MIB::ifDescr"},
+		{"IF-MIB::ifNumber", leaves, "IF-MIB::ifIndex", "SNMPv2-MIB::sysORUpTime"},
+		{"IF-MIB::ifNumber.0", leaves, "IF-MIB::ifIndex", "IF-MIB::ifNumber"},
+		{"IF-MIB::ifTable", nil, "IF-MIB::ifEntry", "IF-MIB::ifNumber"},
+		{"1.3.6.1.2.1.2.2.1.99", leaves, "SNMPv2-MIB::snmpInPkts", "IF-MIB::ifSpecific"},
+		{"1", nil, "SNMPv2-SMI::org", ""},
+		{"0", nil, "iso", ""},
+		{"2", nil, "", "SNMPv2-MIB::snmpMIBGroups"},
+	}
+	for _, test := range tests {
+		oid, err := mib.OID(test.from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		next, nextOID := mib.Next(oid, test.filter)
+		if test.next == "" {
+			if next != nil {
+				t.Errorf("Next(%s): expected nil, got %v", test.from, next)
+			}
+		} else if next == nil || next.String() != test.next {
+			t.Errorf("Next(%s): got %v, expected %s", test.from, next, test.next)
+		} else if expected, _ := mib.OID(test.next); !nextOID.Equal(expected) {
+			t.Errorf("Next(%s): got OID %s, expected %s", test.from, nextOID, expected)
+		}
+
+		prev, prevOID := mib.Prev(oid, test.filter)
+		if test.prev == "" {
+			if prev != nil {
+				t.Errorf("Prev(%s): expected nil, got %v", test.from, prev)
+			}
+		} else if prev == nil || prev.String() != test.prev {
+			t.Errorf("Prev(%s): got %v, expected %s", test.from, prev, test.prev)
+		} else if expected, _ := mib.OID(test.prev); !prevOID.Equal(expected) {
+			t.Errorf("Prev(%s): got OID %s, expected %s", test.from, prevOID, expected)
+		}
+	}
+}
+
+func TestChildren(t *testing.T) {
+	mib := smi.NewMIB("testdata")
+	err := mib.LoadModules("IF-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	kids := mib.Symbols["ifEntry"].Children()
+	if len(kids) != 22 {
+		t.Fatalf("got %d children of ifEntry, expected 22", len(kids))
+	}
+	for i, kid := range kids {
+		if kid.ID != i+1 {
+			t.Errorf("got child %v with ID %d at position %d", kid, kid.ID, i)
+		}
+	}
+	if kids := mib.Symbols["ifIndex"].Children(); len(kids) != 0 {
+		t.Errorf("got children %v of ifIndex, expected none", kids)
+	}
+}
+
+func TestNextAddedChild(t *testing.T) {
+	mib := smi.NewMIB("testdata")
+	err := mib.LoadModules("IF-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := mib.Symbols["ifEntry"]
+	entry.ChildByID[99] = &smi.Symbol{Name: "ifExtra", ID: 99, Parent: entry}
+
+	oid, err := mib.OID("ifSpecific")
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, nextOID := mib.Next(oid, nil)
+	if next == nil || next.Name != "ifExtra" {
+		t.Fatalf("got %v, expected ifExtra", next)
+	}
+	if prev, _ := mib.Prev(nextOID.Append(0), nil); prev != next {
+		t.Errorf("got %v, expected ifExtra", prev)
+	}
+	entryOID, _ := mib.OID("ifEntry")
+	var last *smi.Symbol
+	for sym := range mib.Subtree(entryOID) {
+		last = sym
+	}
+	if last != next {
+		t.Errorf("got last symbol %v in subtree, expected ifExtra", last)
+	}
+}
+
+func TestFirstIn(t *testing.T) {
+	mib := smi.NewMIB("testdata")
+	err := mib.LoadModules("IF-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		in       string
+		expected string
+	}{
+		{"ifTable", "IF-MIB::ifIndex"},
+		{"interfaces", "IF-MIB::ifNumber"},
+		{"ifIndex", ""},
+		{"ifIndex.1", ""},
+	}
+	for _, test := range tests {
+		oid, err := mib.OID(test.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sym, _ := mib.FirstIn(oid, smi.LeafObjects)
+		if test.expected == "" {
+			if sym != nil {
+				t.Errorf("FirstIn(%s): expected nil, got %v", test.in, sym)
+			}
+		} else if sym == nil || sym.String() != test.expected {
+			t.Errorf("FirstIn(%s): got %v, expected %s", test.in, sym, test.expected)
+		}
+	}
+}
+
+func TestWalk(t *testing.T) {
+	mib := smi.NewMIB("testdata")
+	err := mib.LoadModules("IF-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	interfaces, err := mib.OID("interfaces")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	mib.Walk(interfaces, func(sym *smi.Symbol, oid smi.OID) smi.WalkAction {
+		names = append(names, sym.Name)
+		if sym.Name == "ifEntry" {
+			return smi.WalkSkip
+		}
+		return smi.WalkContinue
+	})
+	expected := []string{"interfaces", "ifNumber", "ifTable", "ifEntry"}
+	if strings.Join(names, " ") != strings.Join(expected, " ") {
+		t.Errorf("got %v, expected %v", names, expected)
+	}
+
+	names = nil
+	mib.WalkSymbol(mib.Symbols["ifEntry"], func(sym *smi.Symbol, oid smi.OID) smi.WalkAction {
+		names = append(names, sym.Name)
+		if sym.Name == "ifDescr" {
+			return smi.WalkStop
+		}
+		return smi.WalkContinue
+	})
+	expected = []string{"ifEntry", "ifIndex", "ifDescr"}
+	if strings.Join(names, " ") != strings.Join(expected, " ") {
+		t.Errorf("got %v, expected %v", names, expected)
+	}
+
+	for _, oid := range []smi.OID{{}, {2}, {1, 3, 6, 1, 2, 1, 2, 1, 0}} {
+		mib.Walk(oid, func(sym *smi.Symbol, oid smi.OID) smi.WalkAction {
+			t.Errorf("unexpected symbol %v", sym)
+			return smi.WalkContinue
+		})
+	}
+}
+
+func TestIterators(t *testing.T) {
+	mib := smi.NewMIB("testdata")
+	err := mib.LoadModules("IF-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var visited []string
+	mib.VisitSymbols(func(sym *smi.Symbol, oid smi.OID) {
+		visited = append(visited, sym.String()+" "+oid.String())
+	})
+	var iterated []string
+	var prev smi.OID
+	for sym, oid := range mib.All() {
+		iterated = append(iterated, sym.String()+" "+oid.String())
+		if prev != nil && prev.Compare(oid) >= 0 {
+			t.Errorf("%s not after %s", oid, prev)
+		}
+		prev = oid
+	}
+	if strings.Join(visited, "\n") != strings.Join(iterated, "\n") {
+		t.Error("All and VisitSymbols differ")
+	}
+
+	ifTable, _ := mib.OID("ifTable")
+	var names []string
+	for sym, oid := range mib.Subtree(ifTable) {
+		if !oid.HasPrefix(ifTable) {
+			t.Errorf("%s not in subtree", oid)
+		}
+		names = append(names, sym.Name)
+		if len(names) == 3 {
+			break
+		}
+	}
+	expected := []string{"ifTable", "ifEntry", "ifIndex"}
+	if strings.Join(names, " ") != strings.Join(expected, " ") {
+		t.Errorf("got %v, expected %v", names, expected)
+	}
+}
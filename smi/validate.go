@@ -0,0 +1,240 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi
+
+import (
+	"fmt"
+	"math"
+)
+
+// A ValidationError reports why a value cannot be written to an object
+// instance. Name is the instance name as returned by SymbolString and Symbol
+// is the object, or nil if the object ID is not below any loaded symbol.
+type ValidationError struct {
+	Name   string
+	Symbol *Symbol
+	Reason string
+}
+
+// Error returns the reason prefixed with the instance name and the position
+// of the object's definition, in the format used by Diagnostic.
+func (e *ValidationError) Error() string {
+	msg := e.Name + ": " + e.Reason
+	if e.Symbol != nil && e.Symbol.Module != nil && e.Symbol.Node != nil && e.Symbol.Node.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", e.Symbol.Module.File, e.Symbol.Node.Line, msg)
+	}
+	return msg
+}
+
+// Values of the RowStatus textual convention
+const (
+	rowActive        = 1
+	rowNotInService  = 2
+	rowNotReady      = 3
+	rowCreateAndGo   = 4
+	rowCreateAndWait = 5
+	rowDestroy       = 6
+)
+
+// Values of the StorageType textual convention that cannot be written
+const (
+	storagePermanent = 4
+	storageReadOnly  = 5
+)
+
+// ValidateSet checks that value can be written to the object instance oid
+// with an SNMP SET request. The object must be a scalar or a column with a
+// MAX-ACCESS of read-write, read-create or write-only and oid must name a
+// valid instance of it. The value must suit the base type of the object, as
+// described for Varbind, and meet the enumeration, range and SIZE
+// constraints of its syntax and of the textual conventions it derives from.
+// Values that RowStatus and StorageType objects never accept, such as
+// notReady(3), are rejected. The error is a *ValidationError.
+func (mib *MIB) ValidateSet(oid OID, value interface{}) error {
+	_, _, err := mib.validateSet(oid, value)
+	return err
+}
+
+// ValidateTransition checks a SET of the object instance oid from its
+// current value to value. It makes the checks of ValidateSet and, for
+// objects whose syntax derives from RowStatus, StorageType or TestAndIncr,
+// checks that the change is allowed by the textual convention as defined in
+// RFC 2579. Current is nil if the instance does not exist; for a RowStatus
+// object this means the row does not exist. The error is a *ValidationError.
+func (mib *MIB) ValidateTransition(oid OID, current, value interface{}) error {
+	sym, v, err := mib.validateSet(oid, value)
+	if err != nil {
+		return err
+	}
+	invalid := func(format string, args ...interface{}) error {
+		return &ValidationError{Name: mib.SymbolString(oid), Symbol: sym, Reason: fmt.Sprintf(format, args...)}
+	}
+	n, isInteger := v.(int64)
+	if !isInteger {
+		return nil
+	}
+	var cur int64
+	if current != nil {
+		var ok bool
+		if cur, ok = integerValue(current); !ok {
+			return invalid("current value %v is not an integer", current)
+		}
+	}
+
+	switch {
+	case mib.derivesFrom(sym, "RowStatus"):
+		switch {
+		case current == nil && n != rowCreateAndGo && n != rowCreateAndWait && n != rowDestroy:
+			return invalid("row does not exist; %s can only be createAndGo(4), createAndWait(5) or destroy(6)", sym.Name)
+		case current != nil && (n == rowCreateAndGo || n == rowCreateAndWait):
+			return invalid("row already exists; %s is %s", sym.Name, rowStatusName(cur))
+		case cur == rowNotReady && n == rowActive:
+			return invalid("row is notReady(3) and cannot be made active")
+		}
+	case mib.derivesFrom(sym, "StorageType"):
+		if current != nil && n != cur && (cur == storagePermanent || cur == storageReadOnly) {
+			return invalid("storage type %s cannot be changed", storageTypeName(cur))
+		}
+	case mib.derivesFrom(sym, "TestAndIncr"):
+		if current == nil {
+			return invalid("TestAndIncr object has no current value")
+		}
+		if n != cur {
+			return invalid("value %d does not match the current value %d of the TestAndIncr object", n, cur)
+		}
+	}
+	return nil
+}
+
+// validateSet makes the checks of ValidateSet and returns the object and the
+// value converted to the type returned by ParseValue.
+func (mib *MIB) validateSet(oid OID, value interface{}) (*Symbol, interface{}, error) {
+	var sym *Symbol
+	var idx OID
+	if len(oid) > 0 {
+		sym, idx = mib.Symbol(oid)
+	}
+	invalid := func(format string, args ...interface{}) error {
+		return &ValidationError{Name: mib.SymbolString(oid), Symbol: sym, Reason: fmt.Sprintf(format, args...)}
+	}
+	if sym == nil {
+		return nil, nil, invalid("object is not defined in the loaded modules")
+	}
+	if !sym.isObjectType() || sym.IsTable() || sym.IsRow() {
+		return sym, nil, invalid("%s is not a scalar or column object", sym.Name)
+	}
+	switch sym.Node.Access {
+	case "read-write", "read-create", "write-only":
+	default:
+		return sym, nil, invalid("%s has MAX-ACCESS %s", sym.Name, sym.Node.Access)
+	}
+	if sym.IsScalar() {
+		if len(idx) != 1 || idx[0] != 0 {
+			return sym, nil, invalid("instance of scalar %s must be %s.0", sym.Name, sym.Name)
+		}
+	} else if _, ok := mib.decodeIndex(sym.Parent, idx, false); !ok {
+		return sym, nil, invalid("index %s does not match the INDEX of %s", idx, sym.Parent.Name)
+	}
+
+	syntax, err := mib.objectSyntax(sym)
+	if err != nil {
+		return sym, nil, invalid("cannot resolve type %s", sym.Node.Syntax.Type)
+	}
+	v, err := typedValue(syntax, value)
+	if err == nil {
+		err = checkConstraints(syntax, v)
+	}
+	if err != nil {
+		return sym, nil, invalid("%v", err)
+	}
+	if n, ok := v.(int64); ok {
+		switch {
+		case n == rowNotReady && mib.derivesFrom(sym, "RowStatus"):
+			return sym, nil, invalid("RowStatus value notReady(3) cannot be written")
+		case (n == storagePermanent || n == storageReadOnly) && mib.derivesFrom(sym, "StorageType"):
+			return sym, nil, invalid("StorageType value %s cannot be written", storageTypeName(n))
+		}
+	}
+	return sym, v, nil
+}
+
+// typedValue converts a value of the Go types described for Varbind, or a
+// Value of the ValueType of the base type of syntax, to the type that
+// ParseValue returns for the base type.
+func typedValue(syntax Syntax, value interface{}) (interface{}, error) {
+	// The tag of a Value must match the base type, as an agent would answer
+	// wrongType otherwise.
+	if v, ok := value.(Value); ok {
+		if t, known := syntaxValueTypes[syntax.Type]; !known || v.Type != t {
+			return nil, fmt.Errorf("%v value does not suit type %s", v.Type, syntax.Type)
+		}
+	}
+	value = plainValue(value)
+	mismatch := fmt.Errorf("%T value does not suit type %s", value, syntax.Type)
+	switch syntax.Type {
+	case "OCTET STRING", "Opaque", "BITS":
+		if octets, ok := octetsValue(value); ok {
+			return octets, nil
+		}
+		return nil, mismatch
+	case "IpAddress":
+		octets, ok := octetsValue(value)
+		if !ok {
+			return nil, mismatch
+		}
+		if len(octets) != 4 {
+			return nil, fmt.Errorf("IpAddress value has %d octets, not 4", len(octets))
+		}
+		return octets, nil
+	case "OBJECT IDENTIFIER":
+		if oid, ok := value.(OID); ok {
+			return oid, nil
+		}
+		return nil, mismatch
+	case "Counter64", "Unsigned64":
+		if u, ok := value.(uint64); ok {
+			return u, nil
+		}
+		if n, ok := integerValue(value); ok {
+			if n < 0 {
+				return nil, fmt.Errorf("value %d is negative", n)
+			}
+			return uint64(n), nil
+		}
+		return nil, mismatch
+	}
+	if _, ok := integerLimits[syntax.Type]; !ok {
+		return nil, fmt.Errorf("values of type %s are not supported", syntax.Type)
+	}
+	if u, ok := value.(uint64); ok {
+		if u > math.MaxInt64 {
+			return nil, fmt.Errorf("value %d is out of range for %s", u, syntax.Type)
+		}
+		return int64(u), nil
+	}
+	if n, ok := integerValue(value); ok {
+		return n, nil
+	}
+	return nil, mismatch
+}
+
+var rowStatusNames = []string{"", "active", "notInService", "notReady", "createAndGo", "createAndWait", "destroy"}
+
+func rowStatusName(n int64) string {
+	if n > 0 && n < int64(len(rowStatusNames)) {
+		return fmt.Sprintf("%s(%d)", rowStatusNames[n], n)
+	}
+	return fmt.Sprint(n)
+}
+
+var storageTypeNames = []string{"", "other", "volatile", "nonVolatile", "permanent", "readOnly"}
+
+func storageTypeName(n int64) string {
+	if n > 0 && n < int64(len(storageTypeNames)) {
+		return fmt.Sprintf("%s(%d)", storageTypeNames[n], n)
+	}
+	return fmt.Sprint(n)
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hallidave/mibtool/smi"
+)
+
+func TestValidateSet(t *testing.T) {
+	mib := smi.NewMIB("testdata")
+	err := mib.LoadModules("IF-MIB", "RMON2-MIB", "SNMPv2-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{"ifAdminStatus.1", 2, ""},
+		{"ifAdminStatus.1", int64(3), ""},
+		{"ifAdminStatus.1", 4, "4 is not one of the values { up(1), down(2), testing(3) }"},
+		{"ifAdminStatus.1", "up", "string value does not suit type INTEGER"},
+		{"ifAdminStatus", 1, "index  does not match the INDEX of ifEntry"},
+		{"ifDescr.1", "eth0", "ifDescr has MAX-ACCESS read-only"},
+		{"ifTable", 1, "ifTable is not a scalar or column object"},
+		{"ifLinkUpDownTrapEnable.3", 1, ""},
+		{"ifAlias.3", strings.Repeat("x", 65), "length 65 is not in the size range 0..64"},
+		{"sysContact.0", "admin", ""},
+		{"sysContact.1", "admin", "instance of scalar sysContact must be sysContact.0"},
+		{"probeDateTime.0", []byte{}, ""},
+		{"probeDateTime.0", make([]byte, 8), ""},
+		{"probeDateTime.0", make([]byte, 11), ""},
+		{"probeDateTime.0", make([]byte, 9), "length 9 is not in the size range 0 | 8 | 11"},
+		{"ifStackStatus.1.2", 4, ""},
+		{"ifStackStatus.1.2", 3, "RowStatus value notReady(3) cannot be written"},
+		{"ifStackStatus.1", 4, "index 1 does not match the INDEX of ifStackEntry"},
+		{"0.5", 1, "object is not defined in the loaded modules"},
+		{"ifAdminStatus.1", smi.Value{Type: smi.ValueInteger, Int: 2}, ""},
+		{"ifAdminStatus.1", smi.Value{Type: smi.ValueInteger, Int: 4}, "4 is not one of the values { up(1), down(2), testing(3) }"},
+		{"ifAlias.3", smi.Value{Type: smi.ValueOctetString, Bytes: []byte("uplink")}, ""},
+		{"ifStackStatus.1.2", smi.Value{Type: smi.ValueNoSuchInstance}, "noSuchInstance value does not suit type INTEGER"},
+		{"ifAdminStatus.1", smi.Value{Type: smi.ValueTimeTicks, Uint: 1}, "TimeTicks value does not suit type INTEGER"},
+		{"ifAdminStatus.1", smi.Value{Type: smi.ValueCounter64, Uint: 1}, "Counter64 value does not suit type INTEGER"},
+		{"ifAlias.1", smi.Value{Type: smi.ValueIPAddress, Bytes: []byte{192, 0, 2, 1}}, "IpAddress value does not suit type OCTET STRING"},
+		{"ifAlias.1", smi.Value{Type: smi.ValueOpaque, Bytes: []byte{1}}, "Opaque value does not suit type OCTET STRING"},
+		{"ifLinkUpDownTrapEnable.1", smi.Value{Type: smi.ValueGauge32, Uint: 1}, "Gauge32 value does not suit type INTEGER"},
+	}
+	for _, test := range tests {
+		oid, err := mib.OID(test.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = mib.ValidateSet(oid, test.value)
+		checkValidationError(t, test.name, err, test.expected)
+	}
+
+	err = mib.ValidateSet(smi.OID{}, 1)
+	checkValidationError(t, "empty OID", err, "object is not defined in the loaded modules")
+
+	// A value decoded from an SNMP message
+	oid, _ := mib.OID("ifAdminStatus.1")
+	value, _, err := smi.DecodeValue([]byte{0x02, 0x01, 0x01})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mib.ValidateSet(oid, value); err != nil {
+		t.Error(err)
+	}
+
+	err = mib.ValidateSet(oid, 9)
+	expected := "/IF-MIB:252: IF-MIB::ifAdminStatus.1: 9 is not one of the values { up(1), down(2), testing(3) }"
+	if err == nil || !strings.HasSuffix(err.Error(), expected) {
+		t.Errorf("got error %v, expected %q", err, expected)
+	}
+	if verr, ok := err.(*smi.ValidationError); !ok || verr.Symbol.Name != "ifAdminStatus" {
+		t.Errorf("got %#v, expected a *ValidationError for ifAdminStatus", err)
+	}
+}
+
+func TestValidateTransition(t *testing.T) {
+	mib := smi.NewMIB("testdata")
+	err := mib.LoadModules("IF-MIB", "SNMPv2-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		current  interface{}
+		value    interface{}
+		expected string
+	}{
+		{"ifStackStatus.1.2", nil, 4, ""},
+		{"ifStackStatus.1.2", nil, 5, ""},
+		{"ifStackStatus.1.2", nil, 6, ""},
+		{"ifStackStatus.1.2", nil, 1, "row does not exist; ifStackStatus can only be createAndGo(4), createAndWait(5) or destroy(6)"},
+		{"ifStackStatus.1.2", 1, 2, ""},
+		{"ifStackStatus.1.2", 2, 1, ""},
+		{"ifStackStatus.1.2", 1, 6, ""},
+		{"ifStackStatus.1.2", 1, 5, "row already exists; ifStackStatus is active(1)"},
+		{"ifStackStatus.1.2", 3, 1, "row is notReady(3) and cannot be made active"},
+		{"ifStackStatus.1.2", 1, 3, "RowStatus value notReady(3) cannot be written"},
+		{"snmpSetSerialNo.0", 17, 17, ""},
+		{"snmpSetSerialNo.0", 17, 18, "value 18 does not match the current value 17 of the TestAndIncr object"},
+		{"snmpSetSerialNo.0", nil, 0, "TestAndIncr object has no current value"},
+		{"ifAdminStatus.1", 1, 2, ""},
+	}
+	for _, test := range tests {
+		oid, err := mib.OID(test.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = mib.ValidateTransition(oid, test.current, test.value)
+		checkValidationError(t, test.name, err, test.expected)
+	}
+}
+
+func checkValidationError(t *testing.T, name string, err error, expected string) {
+	t.Helper()
+	switch {
+	case expected == "" && err != nil:
+		t.Errorf("%s: unexpected error: %v", name, err)
+	case expected != "" && err == nil:
+		t.Errorf("%s: got no error, expected %q", name, expected)
+	case expected != "" && !strings.HasSuffix(err.Error(), ": "+expected):
+		t.Errorf("%s: got error %q, expected %q", name, err, expected)
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// integerLimits are the values allowed by the integer base types.
+var integerLimits = map[string]Range{
+	"INTEGER":    {math.MinInt32, math.MaxInt32},
+	"Integer32":  {math.MinInt32, math.MaxInt32},
+	"Unsigned32": {0, math.MaxUint32},
+	"Counter32":  {0, math.MaxUint32},
+	"Gauge32":    {0, math.MaxUint32},
+	"TimeTicks":  {0, math.MaxUint32},
+	"Integer64":  {math.MinInt64, math.MaxInt64},
+	"Counter64":  {0, math.MaxInt64},
+	"Unsigned64": {0, math.MaxInt64},
+}
+
+// objectSyntax returns the syntax of the object sym resolved to its base type.
+func (mib *MIB) objectSyntax(sym *Symbol) (Syntax, error) {
+	if sym.Node == nil || sym.Node.Syntax == nil {
+		return Syntax{}, fmt.Errorf("%s is not an object with a syntax", sym)
+	}
+	syntax, ok := mib.resolveSyntax(sym.Module, *sym.Node.Syntax)
+	if !ok {
+		return Syntax{}, fmt.Errorf("%s: cannot resolve type %s", sym, sym.Node.Syntax.Type)
+	}
+	return syntax, nil
+}
+
+// ParseValue converts text to a value of the object sym and checks it against
+// the enumeration, range and SIZE constraints of the object's syntax. Text for
+// an object whose textual convention has a DISPLAY-HINT is parsed with
+// ParseOctets or ParseInteger. Enumerated values may be given as a name, a
+// number or both, as in up, 1 or up(1), and BITS values as a list of bit
+// names separated by commas or spaces.
+//
+// The value is an int64 for the integer types, except for Counter64 and
+// Unsigned64 which give a uint64, an OID for OBJECT IDENTIFIER and a []byte
+// for the other types.
+func (mib *MIB) ParseValue(sym *Symbol, text string) (interface{}, error) {
+	syntax, err := mib.objectSyntax(sym)
+	if err != nil {
+		return nil, err
+	}
+	value, err := mib.parseValue(sym, syntax, text)
+	if err == nil {
+		err = checkConstraints(syntax, value)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", sym, err)
+	}
+	return value, nil
+}
+
+func (mib *MIB) parseValue(sym *Symbol, syntax Syntax, text string) (interface{}, error) {
+	hint := mib.DisplayHint(sym)
+	switch syntax.Type {
+	case "OCTET STRING", "Opaque":
+		if hint != "" {
+			return ParseOctets(hint, text)
+		}
+		return []byte(text), nil
+	case "IpAddress":
+		ip := net.ParseIP(text).To4()
+		if ip == nil || strings.Contains(text, ":") {
+			return nil, fmt.Errorf("invalid IP address %q", text)
+		}
+		return []byte(ip), nil
+	case "OBJECT IDENTIFIER":
+		return mib.OID(text)
+	case "BITS":
+		return parseBits(syntax, text)
+	case "Counter64", "Unsigned64":
+		if hint != "" {
+			n, err := ParseInteger(hint, text)
+			if err == nil && n < 0 {
+				err = fmt.Errorf("value %d is negative", n)
+			}
+			return uint64(n), err
+		}
+		n, err := strconv.ParseUint(text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", text)
+		}
+		return n, nil
+	}
+	if _, ok := integerLimits[syntax.Type]; !ok {
+		return nil, fmt.Errorf("values of type %s are not supported", syntax.Type)
+	}
+	if len(syntax.Enums) > 0 {
+		return parseEnum(syntax, text)
+	}
+	if hint != "" {
+		return ParseInteger(hint, text)
+	}
+	n, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %q", text)
+	}
+	return n, nil
+}
+
+// parseEnum converts an enumerated value given as a name, a number or a
+// name followed by its number in parentheses.
+func parseEnum(syntax Syntax, text string) (int64, error) {
+	name, number, hasNumber := strings.Cut(strings.TrimSuffix(text, ")"), "(")
+	if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return n, nil
+	}
+	for _, enum := range syntax.Enums {
+		if enum.Name != name {
+			continue
+		}
+		if hasNumber && number != strconv.FormatInt(enum.Value, 10) {
+			return 0, fmt.Errorf("%s is %s(%d)", text, enum.Name, enum.Value)
+		}
+		return enum.Value, nil
+	}
+	return 0, fmt.Errorf("%q is not one of the values %s", text, formatEnums(syntax.Enums))
+}
+
+// parseBits converts a list of bit names to the octets of a BITS value.
+func parseBits(syntax Syntax, text string) ([]byte, error) {
+	text = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(text), "{"), "}")
+	names := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	var value []byte
+	for _, name := range names {
+		bit, err := parseEnum(syntax, name)
+		if err != nil {
+			return nil, err
+		}
+		if bit < 0 || bit >= 8*256 {
+			return nil, fmt.Errorf("invalid bit number %d", bit)
+		}
+		for len(value) <= int(bit/8) {
+			value = append(value, 0)
+		}
+		value[bit/8] |= 0x80 >> (bit % 8)
+	}
+	return value, nil
+}
+
+// checkConstraints checks a value returned by parseValue against the
+// enumeration, range and size constraints of its syntax.
+func checkConstraints(syntax Syntax, value interface{}) error {
+	switch v := value.(type) {
+	case int64:
+		if len(syntax.Enums) > 0 {
+			if !slices.ContainsFunc(syntax.Enums, func(e NamedNumber) bool { return e.Value == v }) {
+				return fmt.Errorf("%d is not one of the values %s", v, formatEnums(syntax.Enums))
+			}
+			return nil
+		}
+		limits := integerLimits[syntax.Type]
+		if v < limits.Min || v > limits.Max {
+			return fmt.Errorf("value %d is out of range for %s", v, syntax.Type)
+		}
+		if len(syntax.Ranges) > 0 && !inRanges(syntax.Ranges, v) {
+			return fmt.Errorf("value %d is not in the range %s", v, FormatRanges(syntax.Ranges))
+		}
+	case uint64:
+		if len(syntax.Ranges) > 0 && !inRanges(syntax.Ranges, clampUint64(v)) {
+			return fmt.Errorf("value %d is not in the range %s", v, FormatRanges(syntax.Ranges))
+		}
+	case []byte:
+		if syntax.Type == "BITS" {
+			for bit := 0; bit < 8*len(v); bit++ {
+				if v[bit/8]&(0x80>>(bit%8)) == 0 {
+					continue
+				}
+				if !slices.ContainsFunc(syntax.Enums, func(e NamedNumber) bool { return e.Value == int64(bit) }) {
+					return fmt.Errorf("bit %d is not one of the bits %s", bit, formatEnums(syntax.Enums))
+				}
+			}
+			return nil
+		}
+		if len(syntax.Sizes) > 0 && !inRanges(syntax.Sizes, int64(len(v))) {
+			return fmt.Errorf("length %d is not in the size range %s", len(v), FormatRanges(syntax.Sizes))
+		}
+	}
+	return nil
+}
+
+func inRanges(ranges []Range, n int64) bool {
+	for _, r := range ranges {
+		if n >= r.Min && n <= r.Max {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatRanges writes ranges the way they appear in a MIB, as in 0..255 | 1024.
+func FormatRanges(ranges []Range) string {
+	parts := make([]string, len(ranges))
+	for i, r := range ranges {
+		if r.Min == r.Max {
+			parts[i] = strconv.FormatInt(r.Min, 10)
+		} else {
+			parts[i] = fmt.Sprintf("%d..%d", r.Min, r.Max)
+		}
+	}
+	return strings.Join(parts, " | ")
+}
+
+// formatEnums writes named numbers the way they appear in a MIB, as in
+// { up(1), down(2) }.
+func formatEnums(enums []NamedNumber) string {
+	parts := make([]string, len(enums))
+	for i, e := range enums {
+		parts[i] = fmt.Sprintf("%s(%d)", e.Name, e.Value)
+	}
+	return "{ " + strings.Join(parts, ", ") + " }"
+}
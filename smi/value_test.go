@@ -0,0 +1,94 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hallidave/mibtool/smi"
+)
+
+func TestParseValue(t *testing.T) {
+	mib := smi.NewMIB("testdata")
+	err := mib.LoadModules("IF-MIB", "HOST-RESOURCES-MIB", "ALARM-MIB", "BGP4-MIB", "RMON2-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"ifAdminStatus", "up", "1"},
+		{"ifAdminStatus", "down(2)", "2"},
+		{"ifAdminStatus", "3", "3"},
+		{"ifPhysAddress", "00:1a:2b:3c:4d:5e", "[0 26 43 60 77 94]"},
+		{"ifDescr", "eth0", "[101 116 104 48]"},
+		{"ifMtu", "-1500", "-1500"},
+		{"ifIndex", "7", "7"},
+		{"ifHCInOctets", "18446744073709551615", "18446744073709551615"},
+		{"hrSystemDate", "2024-3-1,10:00:00.0,+0:0", "[7 232 3 1 10 0 0 0 43 0 0]"},
+		{"alarmActiveEngineID", "", "[]"},
+		{"alarmActiveEngineID", "12345", "[49 50 51 52 53]"},
+		{"bgpPeerIdentifier", "192.0.2.1", "[192 0 2 1]"},
+		{"ifSpecific", "IF-MIB::ifIndex", "1.3.6.1.2.1.2.2.1.1"},
+		{"protocolDirType", "extensible, addressRecognitionCapable", "[192]"},
+		{"protocolDirType", "{ addressRecognitionCapable }", "[64]"},
+		{"protocolDirType", "", "[]"},
+	}
+	for _, test := range tests {
+		sym := mib.Symbols[test.name]
+		if sym == nil {
+			t.Errorf("%s: symbol not found", test.name)
+			continue
+		}
+		value, err := mib.ParseValue(sym, test.text)
+		if err != nil {
+			t.Errorf("%s %q: %v", test.name, test.text, err)
+			continue
+		}
+		if s := fmt.Sprint(value); s != test.expected {
+			t.Errorf("%s %q: got %s, expected %s", test.name, test.text, s, test.expected)
+		}
+	}
+
+	errors := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"ifAdminStatus", "sideways", `IF-MIB::ifAdminStatus: "sideways" is not one of the values { up(1), down(2), testing(3) }`},
+		{"ifAdminStatus", "4", "IF-MIB::ifAdminStatus: 4 is not one of the values { up(1), down(2), testing(3) }"},
+		{"ifAdminStatus", "up(2)", "IF-MIB::ifAdminStatus: up(2) is up(1)"},
+		{"ifPhysAddress", "00-1a", `IF-MIB::ifPhysAddress: display hint "1x:": "00-1a": expected ':' at "-1a"`},
+		{"ifDescr", strings.Repeat("x", 256), "IF-MIB::ifDescr: length 256 is not in the size range 0..255"},
+		{"ifIndex", "0", "IF-MIB::ifIndex: value 0 is not in the range 1..2147483647"},
+		{"ifMtu", "2147483648", "IF-MIB::ifMtu: value 2147483648 is out of range for Integer32"},
+		{"ifMtu", "many", `IF-MIB::ifMtu: invalid number "many"`},
+		{"alarmActiveEngineID", "1234", "ALARM-MIB::alarmActiveEngineID: length 4 is not in the size range 0 | 5..32"},
+		{"bgpPeerIdentifier", "::1", `BGP4-MIB::bgpPeerIdentifier: invalid IP address "::1"`},
+		{"protocolDirType", "extensible, bogus", `RMON2-MIB::protocolDirType: "bogus" is not one of the values { extensible(0), addressRecognitionCapable(1) }`},
+		{"protocolDirType", "5", "RMON2-MIB::protocolDirType: bit 5 is not one of the bits { extensible(0), addressRecognitionCapable(1) }"},
+		{"ifTable", "1", "IF-MIB::ifTable: values of type SEQUENCE OF are not supported"},
+		{"interfaces", "1", "IF-MIB::interfaces is not an object with a syntax"},
+	}
+	for _, test := range errors {
+		sym := mib.Symbols[test.name]
+		if sym == nil {
+			t.Errorf("%s: symbol not found", test.name)
+			continue
+		}
+		value, err := mib.ParseValue(sym, test.text)
+		if err == nil {
+			t.Errorf("%s %q: got %v, expected error", test.name, test.text, value)
+		} else if err.Error() != test.expected {
+			t.Errorf("%s %q: got error %q, expected %q", test.name, test.text, err, test.expected)
+		}
+	}
+}
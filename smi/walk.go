@@ -0,0 +1,471 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// A WalkVarbind is a varbind read from the text output of the net-snmp
+// commands. Type is the type of the value, taken from its prefix such as
+// STRING: or from the syntax of the object if the value has no prefix. The
+// Value follows the conventions of Varbind and is nil for NULL and the
+// exception values. Line is the line number at which the varbind starts.
+type WalkVarbind struct {
+	Varbind
+	Type ValueType
+	Line int
+}
+
+// maxWalkLine is the longest line accepted by ParseWalk.
+const maxWalkLine = 1 << 20
+
+// walkTypes are the value prefixes written by net-snmp and the types of
+// their values.
+var walkTypes = map[string]ValueType{
+	"INTEGER":         ValueInteger,
+	"STRING":          ValueOctetString,
+	"Hex-STRING":      ValueOctetString,
+	"BITS":            ValueOctetString,
+	"OID":             ValueObjectID,
+	"IpAddress":       ValueIPAddress,
+	"Network Address": ValueIPAddress,
+	"Counter32":       ValueCounter32,
+	"Gauge32":         ValueGauge32,
+	"Unsigned32":      ValueGauge32,
+	"UInteger32":      ValueGauge32,
+	"Timeticks":       ValueTimeTicks,
+	"Counter64":       ValueCounter64,
+	"Opaque":          ValueOpaque,
+	"OPAQUE":          ValueOpaque,
+}
+
+// walkExceptions are the messages written by net-snmp for exception values.
+var walkExceptions = []struct {
+	prefix string
+	typ    ValueType
+}{
+	{"No Such Object", ValueNoSuchObject},
+	{"No Such Instance", ValueNoSuchInstance},
+	{"No more variables", ValueEndOfMibView},
+}
+
+// Tags of the values that net-snmp wraps in an Opaque value
+const (
+	opaqueTag        = 0x9f
+	opaqueCounter64  = 0x76
+	opaqueFloat      = 0x78
+	opaqueDouble     = 0x79
+	opaqueInteger64  = 0x7a
+	opaqueUnsigned64 = 0x7b
+)
+
+// A walkEntry is a varbind whose value text may continue on later lines.
+type walkEntry struct {
+	oid  OID
+	line int
+	text string
+}
+
+// ParseWalk reads the output of snmpwalk, snmpget and the other net-snmp
+// commands, one varbind per line in the format "name = TYPE: value". Names
+// may be numeric, as printed with -On, or in any of the symbolic formats
+// accepted by OID. Values may have any of the type prefixes written by
+// net-snmp, including Hex-STRING, BITS, Network Address and the Opaque
+// Float, Double and 64-bit integer values, which are encoded as net-snmp
+// sends them. Values without a prefix, as printed with -OQ, are read using the
+// syntax of the object. Enumerated values may be given by name, number or
+// both and integers and strings written with the DISPLAY-HINT of the object
+// are converted back to their values.
+//
+// Quoted strings may continue on the following lines until the closing
+// quote, and Hex-STRING, BITS and Opaque values on lines of hexadecimal
+// octets. Any other line that is not a varbind is an error. Blank lines are
+// ignored.
+func (mib *MIB) ParseWalk(r io.Reader) ([]WalkVarbind, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxWalkLine)
+	var varbinds []WalkVarbind
+	var entry *walkEntry
+	flush := func() error {
+		if entry == nil {
+			return nil
+		}
+		t, value, err := mib.parseWalkValue(entry.oid, strings.TrimRight(entry.text, "\n"))
+		if err != nil {
+			return fmt.Errorf("line %d: %s: %v", entry.line, mib.SymbolString(entry.oid), err)
+		}
+		varbinds = append(varbinds, WalkVarbind{Varbind{entry.oid, value}, t, entry.line})
+		entry = nil
+		return nil
+	}
+
+	for lineno := 1; scanner.Scan(); lineno++ {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if entry != nil && inQuotes(entry.text) {
+			entry.text += "\n" + line
+			continue
+		}
+		name, text, ok := splitWalkLine(line)
+		var oid OID
+		var err error
+		if ok {
+			oid, err = mib.OID(name)
+			ok = err == nil
+		}
+		if !ok {
+			switch {
+			case entry != nil && continues(entry.text, line):
+				entry.text += "\n" + line
+			case strings.TrimSpace(line) == "":
+			case err != nil:
+				return nil, fmt.Errorf("line %d: %v", lineno, err)
+			default:
+				return nil, fmt.Errorf("line %d: not a varbind: %q", lineno, line)
+			}
+			continue
+		}
+		if err := flush(); err != nil {
+			return nil, err
+		}
+		entry = &walkEntry{oid: oid, line: lineno, text: text}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return varbinds, nil
+}
+
+// splitWalkLine splits a line in the format "name = value" into the name and
+// the value. The name may contain quoted strings with spaces.
+func splitWalkLine(line string) (string, string, bool) {
+	j, err := scanOIDPart(line, 0, " ")
+	if err != nil || j == 0 {
+		return "", "", false
+	}
+	rest := line[j:]
+	if !strings.HasPrefix(rest, " =") || len(rest) > 2 && rest[2] != ' ' {
+		return "", "", false
+	}
+	return line[:j], strings.TrimPrefix(rest[2:], " "), true
+}
+
+// walkPrefix splits the text of a value into its type prefix and the rest.
+// A Wrong Type message before the prefix is removed. The prefix is empty if
+// the text has none.
+func walkPrefix(text string) (string, string) {
+	if strings.HasPrefix(text, "Wrong Type") {
+		if _, rest, ok := strings.Cut(text, "): "); ok {
+			text = rest
+		}
+	}
+	if prefix, rest, ok := strings.Cut(text, ":"); ok {
+		if _, known := walkTypes[prefix]; known {
+			return prefix, strings.TrimPrefix(rest, " ")
+		}
+	}
+	return "", text
+}
+
+// inQuotes returns true if the text of a value is a quoted string that has
+// not been closed yet.
+func inQuotes(text string) bool {
+	prefix, value := walkPrefix(text)
+	return (prefix == "STRING" || prefix == "") && strings.HasPrefix(value, `"`) && closingQuote(value) < 0
+}
+
+// continues returns true if line continues the text of a Hex-STRING, BITS or
+// Opaque value with more hexadecimal octets. The octets of a BITS value may
+// be followed by the names of the bits that are set.
+func continues(text, line string) bool {
+	switch prefix, _ := walkPrefix(text); prefix {
+	case "Hex-STRING", "BITS", "Opaque", "OPAQUE":
+		fields := strings.Fields(line)
+		n := 0
+		for n < len(fields) && isHexOctet(fields[n]) {
+			n++
+		}
+		return n > 0 && (n == len(fields) || prefix == "BITS")
+	}
+	return false
+}
+
+// isHexOctet returns true if s is an octet written as two hexadecimal digits.
+func isHexOctet(s string) bool {
+	return len(s) == 2 && isBaseDigit(s[0], 16) && isBaseDigit(s[1], 16)
+}
+
+// closingQuote returns the position of the quote that closes the string at
+// the start of s, or -1 if there is none.
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// unquoteWalkString removes the quotes from a string and the backslashes that
+// net-snmp puts before quotes and backslashes within it.
+func unquoteWalkString(s string) ([]byte, error) {
+	end := closingQuote(s)
+	if end < 0 || strings.TrimSpace(s[end+1:]) != "" {
+		return nil, fmt.Errorf("invalid quoted string %s", s)
+	}
+	b := make([]byte, 0, end)
+	for i := 1; i < end; i++ {
+		if s[i] == '\\' && (s[i+1] == '"' || s[i+1] == '\\') {
+			i++
+		}
+		b = append(b, s[i])
+	}
+	return b, nil
+}
+
+// parseWalkValue converts the text of a value of the object instance oid.
+func (mib *MIB) parseWalkValue(oid OID, text string) (ValueType, interface{}, error) {
+	for _, e := range walkExceptions {
+		if strings.HasPrefix(text, e.prefix) {
+			return e.typ, nil, nil
+		}
+	}
+	switch text {
+	case "NULL":
+		return ValueNull, nil, nil
+	case `""`:
+		return ValueOctetString, []byte{}, nil
+	}
+
+	sym, _ := mib.Symbol(oid)
+	var hint string
+	if sym != nil {
+		hint = mib.DisplayHint(sym)
+	}
+	prefix, value := walkPrefix(text)
+	t := walkTypes[prefix]
+	var v interface{}
+	var err error
+	switch prefix {
+	case "":
+		return mib.parseUntypedValue(sym, text)
+	case "INTEGER":
+		v, err = mib.walkInteger(sym, hint, value, integerLimits["INTEGER"])
+	case "Counter32", "Gauge32", "Unsigned32", "UInteger32", "Timeticks":
+		v, err = mib.walkInteger(sym, hint, value, integerLimits["Unsigned32"])
+	case "Counter64":
+		v, err = walkUnsigned64(hint, value)
+	case "STRING":
+		if strings.HasPrefix(value, `"`) {
+			v, err = unquoteWalkString(value)
+			break
+		}
+		b, hintErr := ParseOctets(hint, value)
+		if hint == "" || hintErr != nil {
+			b = []byte(value)
+		}
+		v = b
+	case "Hex-STRING":
+		v, err = hex.DecodeString(strings.Join(strings.Fields(value), ""))
+	case "BITS":
+		v, err = walkBits(value)
+	case "OID":
+		v, err = mib.OID(strings.TrimSpace(value))
+	case "IpAddress":
+		ip := net.ParseIP(strings.TrimSpace(value)).To4()
+		if ip == nil || strings.Contains(value, ":") {
+			err = fmt.Errorf("invalid IP address %q", value)
+		}
+		v = []byte(ip)
+	case "Network Address":
+		var b []byte
+		b, err = hex.DecodeString(strings.ReplaceAll(strings.TrimSpace(value), ":", ""))
+		if err == nil && len(b) != net.IPv4len {
+			err = fmt.Errorf("network address %q is not 4 octets", value)
+		}
+		v = b
+	case "Opaque", "OPAQUE":
+		v, err = walkOpaque(value)
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	return t, v, nil
+}
+
+// parseUntypedValue converts a value printed without a type prefix using the
+// syntax of its object.
+func (mib *MIB) parseUntypedValue(sym *Symbol, text string) (ValueType, interface{}, error) {
+	if sym == nil || sym.Node == nil || sym.Node.Syntax == nil {
+		return 0, nil, fmt.Errorf("value %q has no type", text)
+	}
+	syntax, err := mib.objectSyntax(sym)
+	if err != nil {
+		return 0, nil, err
+	}
+	var t ValueType
+	switch syntax.Type {
+	case "INTEGER", "Integer32", "Integer64":
+		t = ValueInteger
+	case "Counter32":
+		t = ValueCounter32
+	case "Gauge32", "Unsigned32":
+		t = ValueGauge32
+	case "TimeTicks":
+		t = ValueTimeTicks
+	case "Counter64", "Unsigned64":
+		t = ValueCounter64
+	case "IpAddress":
+		t = ValueIPAddress
+	case "Opaque":
+		t = ValueOpaque
+	case "OBJECT IDENTIFIER":
+		t = ValueObjectID
+	default:
+		t = ValueOctetString
+	}
+	switch {
+	case strings.HasPrefix(text, `"`) && (t == ValueOctetString || t == ValueOpaque):
+		b, err := unquoteWalkString(text)
+		return t, b, err
+	case strings.HasPrefix(text, "(") && t == ValueTimeTicks:
+		text, _, _ = strings.Cut(text[1:], ")")
+	case t != ValueOctetString && t != ValueOpaque:
+		text = strings.TrimSpace(text)
+	}
+	v, err := mib.parseValue(sym, syntax, text)
+	return t, v, err
+}
+
+// walkInteger converts an integer value, which may be an enumerated value
+// given as up(1) or up, a TimeTicks value given as (100) 0:00:01.00, or a
+// number formatted with the display hint of the object. Text following the
+// number, such as the UNITS of the object, is ignored.
+func (mib *MIB) walkInteger(sym *Symbol, hint, text string, limits Range) (int64, error) {
+	if strings.HasPrefix(text, "(") {
+		text, _, _ = strings.Cut(text[1:], ")")
+	}
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("missing value")
+	}
+	field := fields[0]
+	if name, number, ok := strings.Cut(strings.TrimSuffix(field, ")"), "("); ok && name != "" {
+		field = number
+	}
+	n, err := strconv.ParseInt(field, 10, 64)
+	if err != nil && sym != nil {
+		if syntax, synErr := mib.objectSyntax(sym); synErr == nil && len(syntax.Enums) > 0 {
+			n, err = parseEnum(syntax, field)
+		} else if hint != "" {
+			n, err = ParseInteger(hint, field)
+		}
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", text)
+	}
+	if n < limits.Min || n > limits.Max {
+		return 0, fmt.Errorf("value %d is out of range", n)
+	}
+	return n, nil
+}
+
+func walkUnsigned64(hint, text string) (uint64, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("missing value")
+	}
+	n, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil && hint != "" {
+		var i int64
+		i, err = ParseInteger(hint, fields[0])
+		if err == nil && i < 0 {
+			err = fmt.Errorf("value %d is negative", i)
+		}
+		n = uint64(i)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", text)
+	}
+	return n, nil
+}
+
+// walkBits converts a BITS value, which net-snmp writes as hexadecimal octets
+// followed by the names of the bits that are set, as in 80 00 linkUp(0).
+func walkBits(text string) ([]byte, error) {
+	var b []byte
+	for _, field := range strings.Fields(text) {
+		if !isHexOctet(field) {
+			break
+		}
+		c, _ := strconv.ParseUint(field, 16, 8)
+		b = append(b, byte(c))
+	}
+	if b == nil {
+		return nil, fmt.Errorf("invalid BITS value %q", text)
+	}
+	return b, nil
+}
+
+// walkOpaque converts an Opaque value, which is either hexadecimal octets or
+// one of the values that net-snmp wraps in an Opaque, such as Float: 1.5. The
+// wrapped values are encoded as net-snmp does.
+func walkOpaque(text string) ([]byte, error) {
+	kind, value, ok := strings.Cut(text, ": ")
+	value = strings.TrimSpace(value)
+	if !ok {
+		return hex.DecodeString(strings.Join(strings.Fields(text), ""))
+	}
+	var tag byte
+	var content []byte
+	var err error
+	switch kind {
+	case "Float":
+		var f float64
+		f, err = strconv.ParseFloat(value, 32)
+		tag = opaqueFloat
+		content = binary.BigEndian.AppendUint32(nil, math.Float32bits(float32(f)))
+	case "Double":
+		var f float64
+		f, err = strconv.ParseFloat(value, 64)
+		tag = opaqueDouble
+		content = binary.BigEndian.AppendUint64(nil, math.Float64bits(f))
+	case "Int64":
+		var i int64
+		i, err = strconv.ParseInt(value, 10, 64)
+		tag = opaqueInteger64
+		content = encodeInt(i)
+	case "UInt64", "Counter64":
+		var u uint64
+		u, err = strconv.ParseUint(value, 10, 64)
+		tag = opaqueUnsigned64
+		if kind == "Counter64" {
+			tag = opaqueCounter64
+		}
+		content = encodeUint(u)
+	default:
+		return nil, fmt.Errorf("unknown Opaque value type %s", kind)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value %q", kind, value)
+	}
+	b := append([]byte{opaqueTag, tag}, encodeLength(len(content))...)
+	return append(b, content...), nil
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2019 David R. Halliday. All rights reserved.
+//
+// Use of this source code is governed by an MIT-style license
+// that can be found in the LICENSE file.
+
+package smi_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hallidave/mibtool/smi"
+)
+
+const testWalk = `.1.3.6.1.2.1.1.1.0 = STRING: "Linux router 5.10
+built with \"gcc\" \\ clang"
+SNMPv2-MIB::sysUpTime.0 = Timeticks: (9038405) 1 day, 1:06:24.05
+IF-MIB::ifDescr.1 = STRING: eth0
+IF-MIB::ifType.1 = INTEGER: ethernetCsmacd(6)
+IF-MIB::ifMtu.1 = INTEGER: -1500
+ifSpeed.1 = Gauge32: 1000000000
+IF-MIB::ifPhysAddress.1 = STRING: 0:1a:2b:3c:4d:5e
+IF-MIB::ifAdminStatus.1 = INTEGER: down
+IF-MIB::ifOperStatus.1 = up
+IF-MIB::ifInOctets.1 = Counter32: 123456
+
+IF-MIB::ifHCInOctets.1 = Counter64: 18446744073709551615
+IF-MIB::ifSpecific.1 = OID: SNMPv2-SMI::enterprises.9
+.1.3.6.1.2.1.2.2.1.6.2 = Hex-STRING: 00 1A 2B 3C
+4D 5E
+IF-MIB::ifAlias.2 = ""
+IF-MIB::ifStackStatus.0.1 = INTEGER: active(1)
+IF-MIB::ifLastChange.2 = Wrong Type (should be Timeticks): INTEGER: 5
+IF-MIB::ifOutQLen.1 = No Such Instance currently exists at this OID
+HOST-RESOURCES-MIB::hrSystemDate.0 = STRING: 2024-3-1,10:0:0.0,+1:0
+HOST-RESOURCES-MIB::hrMemorySize.0 = INTEGER: 2048 KBytes
+BGP4-MIB::bgpPeerRemoteAddr.192.0.2.1 = IpAddress: 192.0.2.1
+.1.3.6.1.4.1.99999.1.0 = Opaque: Float: 1.500000
+.1.3.6.1.4.1.99999.2.0 = NULL
+.1.3.6.1.4.1.99999.3.0 = BITS: 80 01 a(0) 15
+.1.3.6.1.4.1.99999.4.0 = Network Address: C0:00:02:01
+.1.3.6.1.4.1.99999.5.0 = No more variables left in this MIB View (It is past the end of the MIB tree)
+`
+
+func TestParseWalk(t *testing.T) {
+	mib := smi.NewMIB("testdata")
+	err := mib.LoadModules("IF-MIB", "HOST-RESOURCES-MIB", "BGP4-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	varbinds, err := mib.ParseWalk(strings.NewReader(testWalk))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`1: SNMPv2-MIB::sysDescr.0 OCTET STRING "Linux router 5.10\nbuilt with \"gcc\" \\ clang"`,
+		`3: SNMPv2-MIB::sysUpTime.0 TimeTicks 9038405`,
+		`4: IF-MIB::ifDescr.1 OCTET STRING "eth0"`,
+		`5: IF-MIB::ifType.1 INTEGER 6`,
+		`6: IF-MIB::ifMtu.1 INTEGER -1500`,
+		`7: IF-MIB::ifSpeed.1 Gauge32 1000000000`,
+		`8: IF-MIB::ifPhysAddress.1 OCTET STRING "\x00\x1a+<M^"`,
+		`9: IF-MIB::ifAdminStatus.1 INTEGER 2`,
+		`10: IF-MIB::ifOperStatus.1 INTEGER 1`,
+		`11: IF-MIB::ifInOctets.1 Counter32 123456`,
+		`13: IF-MIB::ifHCInOctets.1 Counter64 18446744073709551615`,
+		`14: IF-MIB::ifSpecific.1 OBJECT IDENTIFIER 1.3.6.1.4.1.9`,
+		`15: IF-MIB::ifPhysAddress.2 OCTET STRING "\x00\x1a+<M^"`,
+		`17: IF-MIB::ifAlias.2 OCTET STRING ""`,
+		`18: IF-MIB::ifStackStatus.0.1 INTEGER 1`,
+		`19: IF-MIB::ifLastChange.2 INTEGER 5`,
+		`20: IF-MIB::ifOutQLen.1 noSuchInstance <nil>`,
+		`21: HOST-RESOURCES-MIB::hrSystemDate.0 OCTET STRING "\a\xe8\x03\x01\n\x00\x00\x00+\x01\x00"`,
+		`22: HOST-RESOURCES-MIB::hrMemorySize.0 INTEGER 2048`,
+		`23: BGP4-MIB::bgpPeerRemoteAddr.192.0.2.1 IpAddress "\xc0\x00\x02\x01"`,
+		`24: SNMPv2-SMI::enterprises.99999.1.0 Opaque "\x9fx\x04?\xc0\x00\x00"`,
+		`25: SNMPv2-SMI::enterprises.99999.2.0 NULL <nil>`,
+		`26: SNMPv2-SMI::enterprises.99999.3.0 OCTET STRING "\x80\x01"`,
+		`27: SNMPv2-SMI::enterprises.99999.4.0 IpAddress "\xc0\x00\x02\x01"`,
+		`28: SNMPv2-SMI::enterprises.99999.5.0 endOfMibView <nil>`,
+	}
+	if len(varbinds) != len(expected) {
+		t.Errorf("got %d varbinds, expected %d", len(varbinds), len(expected))
+	}
+	for i, vb := range varbinds {
+		value := fmt.Sprint(vb.Value)
+		if b, ok := vb.Value.([]byte); ok {
+			value = fmt.Sprintf("%q", b)
+		}
+		s := fmt.Sprintf("%d: %s %s %s", vb.Line, mib.SymbolString(vb.OID), vb.Type, value)
+		if i >= len(expected) || s != expected[i] {
+			t.Errorf("got %s", s)
+			if i < len(expected) {
+				t.Errorf("expected %s", expected[i])
+			}
+		}
+	}
+}
+
+func TestParseWalkErrors(t *testing.T) {
+	mib := smi.NewMIB("testdata")
+	err := mib.LoadModules("IF-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		walk     string
+		expected string
+	}{
+		{"IF-MIB::ifMtu.1 = INTEGER: 1500\nsnmpwalk: Timeout\n", `line 2: not a varbind: "snmpwalk: Timeout"`},
+		{"IF-MIB::ifMtu.1 = INTEGER: big\n", `line 1: IF-MIB::ifMtu.1: invalid number "big"`},
+		{"IF-MIB::ifMtu.1 = INTEGER: 2147483648\n", `line 1: IF-MIB::ifMtu.1: value 2147483648 is out of range`},
+		{"IF-MIB::ifDescr.1 = STRING: \"eth0\n", `line 1: IF-MIB::ifDescr.1: invalid quoted string "eth0`},
+		{"\nFOO-MIB::fooBar.0 = INTEGER: 1\n", `line 2: module FOO-MIB not in MIB`},
+		{".1.3.6.1.4.1.99999.1.0 = 17\n", `line 1: SNMPv2-SMI::enterprises.99999.1.0: value "17" has no type`},
+		{"IF-MIB::ifDescr.1 = STRING: eth0\nTimeout: No Response from localhost\n", `line 2: not a varbind: "Timeout: No Response from localhost"`},
+		{"IF-MIB::ifDescr.1 = STRING: \"eth0\"\nTimeout: No Response from localhost\n", `line 2: not a varbind: "Timeout: No Response from localhost"`},
+		{"IF-MIB::ifPhysAddress.1 = Hex-STRING: 00 1A 2B\n3C 4D 5E\nTimeout: No Response\n", `line 3: not a varbind: "Timeout: No Response"`},
+		{"IF-MIB::ifPhysAddress.1 = Hex-STRING: 00 1A 2B\n3C 4D 5E end\n", `line 2: not a varbind: "3C 4D 5E end"`},
+	}
+	for _, test := range tests {
+		_, err := mib.ParseWalk(strings.NewReader(test.walk))
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("%q: got error %v, expected %q", test.walk, err, test.expected)
+		}
+	}
+}
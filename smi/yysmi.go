@@ -38,6 +38,16 @@ type smiSymType struct {
 	idList               []string
 	imp                  Import
 	impList              []Import
+	line                 int
+	syntax               Syntax
+	typeDef              Type
+	ranges               []Range
+	rng                  Range
+	number               int64
+	namedNumbers         []NamedNumber
+	namedNumber          NamedNumber
+	indexList            []IndexObject
+	indexObject          IndexObject
 }
 
 const tDOT_DOT = 57346
@@ -244,16 +254,17 @@ var smiToknames = [...]string{
 	"'.'",
 	"'|'",
 }
+
 var smiStatenames = [...]string{}
 
 const smiEofCode = 1
 const smiErrCode = 2
 const smiInitialStackSize = 16
 
-//line smi.y:1934
+//line smi.y:2069
 
 //line yacctab:1
-var smiExca = [...]int{
+var smiExca = [...]int16{
 	-1, 1,
 	1, -1,
 	-2, 0,
@@ -298,8 +309,7 @@ const smiPrivate = 57344
 
 const smiLast = 801
 
-var smiAct = [...]int{
-
+var smiAct = [...]int16{
 	282, 675, 448, 564, 618, 591, 642, 354, 612, 232,
 	629, 595, 583, 146, 585, 560, 527, 447, 12, 536,
 	502, 480, 233, 469, 348, 366, 424, 177, 365, 277,
@@ -382,8 +392,8 @@ var smiAct = [...]int{
 	0, 0, 0, 60, 70, 57, 0, 0, 0, 73,
 	68,
 }
-var smiPact = [...]int{
 
+var smiPact = [...]int16{
 	450, -1000, 450, -1000, 119, -1000, -1000, 257, 414, 467,
 	-1000, -1000, 77, 414, -1000, -1000, -1000, 32, -1000, 377,
 	-1000, -1000, 426, 304, -28, 276, -1000, -1000, 707, -1000,
@@ -454,8 +464,8 @@ var smiPact = [...]int{
 	-1000, -1000, 414, 334, -20, -1000, -1000, 395, -1000, 414,
 	-1000, -1000,
 }
-var smiPgo = [...]int{
 
+var smiPgo = [...]int16{
 	0, 691, 690, 478, 689, 44, 39, 688, 687, 686,
 	684, 683, 680, 679, 677, 269, 676, 672, 351, 670,
 	666, 664, 663, 662, 661, 660, 659, 658, 657, 358,
@@ -478,8 +488,8 @@ var smiPgo = [...]int{
 	493, 491, 490, 489, 488, 487, 486, 485, 484, 483,
 	482, 479, 474,
 }
-var smiR1 = [...]int{
 
+var smiR1 = [...]uint8{
 	0, 1, 1, 2, 2, 3, 4, 4, 156, 156,
 	11, 11, 12, 19, 157, 19, 13, 13, 14, 14,
 	15, 7, 7, 6, 6, 6, 8, 8, 8, 8,
@@ -522,8 +532,8 @@ var smiR1 = [...]int{
 	135, 136, 136, 201, 202, 137, 138, 138, 139, 140,
 	140, 141, 141, 142,
 }
-var smiR2 = [...]int{
 
+var smiR2 = [...]int8{
 	0, 1, 0, 1, 2, 9, 3, 0, 1, 1,
 	1, 0, 3, 0, 0, 3, 1, 0, 1, 2,
 	3, 1, 3, 1, 1, 1, 1, 1, 1, 1,
@@ -566,8 +576,8 @@ var smiR2 = [...]int{
 	0, 1, 2, 0, 0, 11, 2, 0, 1, 4,
 	0, 1, 3, 1,
 }
-var smiChk = [...]int{
 
+var smiChk = [...]int16{
 	-1000, -1, -2, -3, -5, 6, -3, -4, 98, -156,
 	26, 70, -103, -104, -105, -29, 8, 7, 6, 5,
 	99, -105, 102, 19, 8, -19, 32, 103, -11, -12,
@@ -638,8 +648,8 @@ var smiChk = [...]int{
 	7, -84, 98, -202, -141, -142, -88, 28, 99, 101,
 	-101, -142,
 }
-var smiDef = [...]int{
 
+var smiDef = [...]int16{
 	2, -2, 1, 3, 7, 50, 4, 0, 0, 0,
 	8, 9, 0, 320, 321, 323, 324, 83, 84, 0,
 	6, 322, 0, 13, 0, 11, 14, 325, -2, 10,
@@ -710,8 +720,8 @@ var smiDef = [...]int{
 	398, 394, 0, 0, 0, 401, 403, 0, 399, 0,
 	395, 402,
 }
-var smiTok1 = [...]int{
 
+var smiTok1 = [...]int8{
 	1, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
@@ -726,8 +736,8 @@ var smiTok1 = [...]int{
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 98, 107, 99,
 }
-var smiTok2 = [...]int{
 
+var smiTok2 = [...]int8{
 	2, 3, 4, 5, 6, 7, 8, 9, 10, 11,
 	12, 13, 14, 15, 16, 17, 18, 19, 20, 21,
 	22, 23, 24, 25, 26, 27, 28, 29, 30, 31,
@@ -739,7 +749,8 @@ var smiTok2 = [...]int{
 	82, 83, 84, 85, 86, 87, 88, 89, 90, 91,
 	92, 93, 94, 95, 96, 97,
 }
-var smiTok3 = [...]int{
+
+var smiTok3 = [...]int8{
 	0,
 }
 
@@ -821,9 +832,9 @@ func smiErrorMessage(state, lookAhead int) string {
 	expected := make([]int, 0, 4)
 
 	// Look for shiftable tokens.
-	base := smiPact[state]
+	base := int(smiPact[state])
 	for tok := TOKSTART; tok-1 < len(smiToknames); tok++ {
-		if n := base + tok; n >= 0 && n < smiLast && smiChk[smiAct[n]] == tok {
+		if n := base + tok; n >= 0 && n < smiLast && int(smiChk[int(smiAct[n])]) == tok {
 			if len(expected) == cap(expected) {
 				return res
 			}
@@ -833,13 +844,13 @@ func smiErrorMessage(state, lookAhead int) string {
 
 	if smiDef[state] == -2 {
 		i := 0
-		for smiExca[i] != -1 || smiExca[i+1] != state {
+		for smiExca[i] != -1 || int(smiExca[i+1]) != state {
 			i += 2
 		}
 
 		// Look for tokens that we accept or reduce.
 		for i += 2; smiExca[i] >= 0; i += 2 {
-			tok := smiExca[i]
+			tok := int(smiExca[i])
 			if tok < TOKSTART || smiExca[i+1] == 0 {
 				continue
 			}
@@ -870,30 +881,30 @@ func smilex1(lex smiLexer, lval *smiSymType) (char, token int) {
 	token = 0
 	char = lex.Lex(lval)
 	if char <= 0 {
-		token = smiTok1[0]
+		token = int(smiTok1[0])
 		goto out
 	}
 	if char < len(smiTok1) {
-		token = smiTok1[char]
+		token = int(smiTok1[char])
 		goto out
 	}
 	if char >= smiPrivate {
 		if char < smiPrivate+len(smiTok2) {
-			token = smiTok2[char-smiPrivate]
+			token = int(smiTok2[char-smiPrivate])
 			goto out
 		}
 	}
 	for i := 0; i < len(smiTok3); i += 2 {
-		token = smiTok3[i+0]
+		token = int(smiTok3[i+0])
 		if token == char {
-			token = smiTok3[i+1]
+			token = int(smiTok3[i+1])
 			goto out
 		}
 	}
 
 out:
 	if token == 0 {
-		token = smiTok2[1] /* unknown char */
+		token = int(smiTok2[1]) /* unknown char */
 	}
 	if smiDebug >= 3 {
 		__yyfmt__.Printf("lex %s(%d)\n", smiTokname(token), uint(char))
@@ -948,7 +959,7 @@ smistack:
 	smiS[smip].yys = smistate
 
 sminewstate:
-	smin = smiPact[smistate]
+	smin = int(smiPact[smistate])
 	if smin <= smiFlag {
 		goto smidefault /* simple state */
 	}
@@ -959,8 +970,8 @@ sminewstate:
 	if smin < 0 || smin >= smiLast {
 		goto smidefault
 	}
-	smin = smiAct[smin]
-	if smiChk[smin] == smitoken { /* valid shift */
+	smin = int(smiAct[smin])
+	if int(smiChk[smin]) == smitoken { /* valid shift */
 		smircvr.char = -1
 		smitoken = -1
 		smiVAL = smircvr.lval
@@ -973,7 +984,7 @@ sminewstate:
 
 smidefault:
 	/* default state action */
-	smin = smiDef[smistate]
+	smin = int(smiDef[smistate])
 	if smin == -2 {
 		if smircvr.char < 0 {
 			smircvr.char, smitoken = smilex1(smilex, &smircvr.lval)
@@ -982,18 +993,18 @@ smidefault:
 		/* look through exception table */
 		xi := 0
 		for {
-			if smiExca[xi+0] == -1 && smiExca[xi+1] == smistate {
+			if smiExca[xi+0] == -1 && int(smiExca[xi+1]) == smistate {
 				break
 			}
 			xi += 2
 		}
 		for xi += 2; ; xi += 2 {
-			smin = smiExca[xi+0]
+			smin = int(smiExca[xi+0])
 			if smin < 0 || smin == smitoken {
 				break
 			}
 		}
-		smin = smiExca[xi+1]
+		smin = int(smiExca[xi+1])
 		if smin < 0 {
 			goto ret0
 		}
@@ -1015,10 +1026,10 @@ smidefault:
 
 			/* find a state where "error" is a legal shift action */
 			for smip >= 0 {
-				smin = smiPact[smiS[smip].yys] + smiErrCode
+				smin = int(smiPact[smiS[smip].yys]) + smiErrCode
 				if smin >= 0 && smin < smiLast {
-					smistate = smiAct[smin] /* simulate a shift of "error" */
-					if smiChk[smistate] == smiErrCode {
+					smistate = int(smiAct[smin]) /* simulate a shift of "error" */
+					if int(smiChk[smistate]) == smiErrCode {
 						goto smistack
 					}
 				}
@@ -1054,7 +1065,7 @@ smidefault:
 	smipt := smip
 	_ = smipt // guard against "declared and not used"
 
-	smip -= smiR2[smin]
+	smip -= int(smiR2[smin])
 	// smip is now the index of $0. Perform the default action. Iff the
 	// reduced production is ε, $1 is possibly out of range.
 	if smip+1 >= len(smiS) {
@@ -1065,16 +1076,16 @@ smidefault:
 	smiVAL = smiS[smip+1]
 
 	/* consult goto table to find next state */
-	smin = smiR1[smin]
-	smig := smiPgo[smin]
+	smin = int(smiR1[smin])
+	smig := int(smiPgo[smin])
 	smij := smig + smiS[smip].yys + 1
 
 	if smij >= smiLast {
-		smistate = smiAct[smig]
+		smistate = int(smiAct[smig])
 	} else {
-		smistate = smiAct[smij]
-		if smiChk[smistate] != -smin {
-			smistate = smiAct[smig]
+		smistate = int(smiAct[smij])
+		if int(smiChk[smistate]) != -smin {
+			smistate = int(smiAct[smig])
 		}
 	}
 	// dummy call; replaced with literal code
@@ -1082,95 +1093,95 @@ smidefault:
 
 	case 2:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:359
+//line smi.y:369
 		{
 		}
 	case 4:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:364
+//line smi.y:374
 		{
 		}
 	case 5:
 		smiDollar = smiS[smipt-9 : smipt+1]
-//line smi.y:379
+//line smi.y:389
 		{
-			m := Module{Name: smiDollar[1].id, Imports: smiDollar[7].impList, Nodes: smiDollar[8].nodeList}
+			m := Module{Name: smiDollar[1].id, Imports: smiDollar[7].impList, Nodes: smiDollar[8].nodeList, Types: takeTypes(&smilex)}
 			setModule(&smilex, &m)
 		}
 	case 6:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:386
+//line smi.y:396
 		{
 		}
 	case 7:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:388
+//line smi.y:398
 		{
 		}
 	case 8:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:392
+//line smi.y:402
 		{
 		}
 	case 9:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:394
+//line smi.y:404
 		{
 		}
 	case 11:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:402
+//line smi.y:412
 		{
 			smiVAL.impList = []Import{}
 		}
 	case 12:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:408
+//line smi.y:418
 		{
 			smiVAL.impList = smiDollar[2].impList
 		}
 	case 13:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:414
+//line smi.y:424
 		{
 		}
 	case 14:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:416
+//line smi.y:426
 		{
 		}
 	case 15:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:419
+//line smi.y:429
 		{
 		}
 	case 17:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:424
+//line smi.y:434
 		{
 			smiVAL.impList = []Import{}
 		}
 	case 18:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:430
+//line smi.y:440
 		{
 			smiVAL.impList = []Import{smiDollar[1].imp}
 		}
 	case 19:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:434
+//line smi.y:444
 		{
 			smiVAL.impList = append(smiDollar[1].impList, smiDollar[2].imp)
 		}
 	case 20:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:442
+//line smi.y:452
 		{
-			smiVAL.imp = Import{From: smiDollar[3].id, Symbols: smiDollar[1].idList}
+			smiVAL.imp = Import{From: smiDollar[3].id, Symbols: smiDollar[1].idList, Line: smiDollar[3].line}
 		}
 	case 21:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:448
+//line smi.y:458
 		{
 			if smiDollar[1].id == "" {
 				smiVAL.idList = []string{}
@@ -1180,7 +1191,7 @@ smidefault:
 		}
 	case 22:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:456
+//line smi.y:466
 		{
 			if smiDollar[3].id == "" {
 				smiVAL.idList = smiDollar[1].idList
@@ -1190,33 +1201,33 @@ smidefault:
 		}
 	case 25:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:471
+//line smi.y:481
 		{
 			smiVAL.id = ""
 		}
 	case 26:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:481
+//line smi.y:491
 		{
 		}
 	case 27:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:483
+//line smi.y:493
 		{
 		}
 	case 51:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:520
+//line smi.y:530
 		{
 		}
 	case 52:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:522
+//line smi.y:532
 		{
 		}
 	case 53:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:526
+//line smi.y:536
 		{
 			if smiDollar[1].node.Type != NodeNotSupported {
 				smiVAL.nodeList = []Node{smiDollar[1].node}
@@ -1226,7 +1237,7 @@ smidefault:
 		}
 	case 54:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:534
+//line smi.y:544
 		{
 			if smiDollar[2].node.Type != NodeNotSupported {
 				smiVAL.nodeList = append(smiDollar[1].nodeList, smiDollar[2].node)
@@ -1234,1712 +1245,1831 @@ smidefault:
 		}
 	case 55:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:542
+//line smi.y:552
 		{
+			addType(&smilex, smiDollar[1].typeDef)
+			smiVAL.node = Node{}
 		}
 	case 56:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:545
+//line smi.y:557
 		{
 		}
 	case 57:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:548
+//line smi.y:560
 		{
 		}
 	case 58:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:551
+//line smi.y:563
 		{
 		}
 	case 59:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:554
+//line smi.y:566
 		{
 		}
 	case 60:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:557
+//line smi.y:569
 		{
 		}
 	case 61:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:560
+//line smi.y:572
 		{
 		}
 	case 62:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:563
+//line smi.y:575
 		{
 		}
 	case 63:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:566
+//line smi.y:578
 		{
 		}
 	case 64:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:569
+//line smi.y:581
 		{
 		}
 	case 65:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:572
+//line smi.y:584
 		{
 		}
 	case 66:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:575
+//line smi.y:587
 		{
 		}
 	case 67:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:578
+//line smi.y:590
 		{
 		}
 	case 68:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:588
+//line smi.y:600
 		{
 		}
 	case 69:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:591
+//line smi.y:603
 		{
 		}
 	case 70:
 		smiDollar = smiS[smipt-5 : smipt+1]
-//line smi.y:595
+//line smi.y:607
 		{
 		}
 	case 71:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:599
+//line smi.y:611
 		{
 			smiVAL.id = smiDollar[1].id
 		}
 	case 72:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:600
+//line smi.y:612
 		{
 			smiVAL.id = smiDollar[1].id
 		}
 	case 73:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:601
+//line smi.y:613
 		{
 			smiVAL.id = smiDollar[1].id
 		}
 	case 74:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:602
+//line smi.y:614
 		{
 			smiVAL.id = smiDollar[1].id
 		}
 	case 75:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:603
+//line smi.y:615
 		{
 			smiVAL.id = smiDollar[1].id
 		}
 	case 76:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:604
+//line smi.y:616
 		{
 			smiVAL.id = smiDollar[1].id
 		}
 	case 77:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:605
+//line smi.y:617
 		{
 			smiVAL.id = smiDollar[1].id
 		}
 	case 78:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:606
+//line smi.y:618
 		{
 			smiVAL.id = smiDollar[1].id
 		}
 	case 79:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:607
+//line smi.y:619
 		{
 			smiVAL.id = smiDollar[1].id
 		}
 	case 80:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:608
+//line smi.y:620
 		{
 			smiVAL.id = smiDollar[1].id
 		}
 	case 81:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:612
+//line smi.y:624
 		{
 		}
 	case 82:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:616
+//line smi.y:628
 		{
+			smiVAL.syntax = Syntax{Type: "CHOICE"}
 		}
 	case 83:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:624
+//line smi.y:637
 		{
 		}
 	case 84:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:628
+//line smi.y:641
 		{
 		}
 	case 85:
 		smiDollar = smiS[smipt-7 : smipt+1]
-//line smi.y:635
+//line smi.y:648
 		{
-			smiVAL.node = Node{Label: smiDollar[1].id, Type: NodeObjectID, IDs: smiDollar[6].subidList}
+			smiVAL.node = Node{Label: smiDollar[1].id, Type: NodeObjectID, IDs: smiDollar[6].subidList, Line: smiDollar[1].line}
 		}
 	case 86:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:644
+//line smi.y:657
 		{
 		}
 	case 87:
 		smiDollar = smiS[smipt-4 : smipt+1]
-//line smi.y:647
+//line smi.y:660
 		{
+			smiVAL.typeDef = smiDollar[4].typeDef
+			smiVAL.typeDef.Name = smiDollar[1].id
+			smiVAL.typeDef.Line = smiDollar[1].line
 		}
 	case 88:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:652
+//line smi.y:668
 		{
 		}
 	case 89:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:655
+//line smi.y:671
 		{
 		}
 	case 90:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:658
+//line smi.y:674
 		{
 		}
 	case 92:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:664
+//line smi.y:680
 		{
 		}
 	case 103:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:685
+//line smi.y:701
 		{
+			smiVAL.typeDef = Type{Syntax: smiDollar[1].syntax}
 		}
 	case 104:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:688
+//line smi.y:705
 		{
 		}
 	case 105:
 		smiDollar = smiS[smipt-7 : smipt+1]
-//line smi.y:693
+//line smi.y:710
 		{
 		}
 	case 106:
 		smiDollar = smiS[smipt-11 : smipt+1]
-//line smi.y:697
+//line smi.y:714
 		{
+			smiVAL.typeDef = Type{Syntax: smiDollar[11].syntax, IsTextualConvention: true, DisplayHint: smiDollar[3].text, Status: smiDollar[5].id,
+				Description: smiDollar[7].text, Reference: smiDollar[9].text}
 		}
 	case 107:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:700
+//line smi.y:719
 		{
+			smiVAL.typeDef = Type{Syntax: smiDollar[1].syntax}
 		}
 	case 108:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:706
+//line smi.y:726
 		{
+			smiVAL.syntax = Syntax{Type: "SEQUENCE OF"}
 		}
 	case 109:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:716
+//line smi.y:737
 		{
+			smiVAL.syntax = Syntax{Type: smiDollar[1].id}
 		}
 	case 110:
 		smiDollar = smiS[smipt-4 : smipt+1]
-//line smi.y:722
+//line smi.y:744
 		{
+			smiVAL.syntax = Syntax{Type: "SEQUENCE"}
 		}
 	case 111:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:727
+//line smi.y:750
 		{
 		}
 	case 112:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:730
+//line smi.y:753
 		{
 		}
 	case 113:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:741
+//line smi.y:764
 		{
 		}
 	case 114:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:746
+//line smi.y:769
 		{
+			smiVAL.syntax = smiDollar[1].syntax
 		}
 	case 115:
 		smiDollar = smiS[smipt-4 : smipt+1]
-//line smi.y:749
+//line smi.y:773
 		{
+			smiVAL.syntax = Syntax{Type: "BITS", Enums: smiDollar[3].namedNumbers}
 		}
 	case 116:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:755
+//line smi.y:780
 		{
 		}
 	case 117:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:758
+//line smi.y:783
 		{
 		}
 	case 118:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:761
+//line smi.y:786
 		{
 		}
 	case 119:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:766
+//line smi.y:791
 		{
+			smiVAL.namedNumbers = []NamedNumber{smiDollar[1].namedNumber}
 		}
 	case 120:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:769
+//line smi.y:795
 		{
+			smiVAL.namedNumbers = append(smiDollar[1].namedNumbers, smiDollar[3].namedNumber)
 		}
 	case 121:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:774
+//line smi.y:801
 		{
 		}
 	case 122:
 		smiDollar = smiS[smipt-5 : smipt+1]
-//line smi.y:777
+//line smi.y:804
 		{
+			smiVAL.namedNumber = NamedNumber{Name: smiDollar[1].id, Value: int64(smiDollar[4].unsigned32)}
 		}
 	case 123:
 		smiDollar = smiS[smipt-11 : smipt+1]
-//line smi.y:788
+//line smi.y:816
 		{
-			smiVAL.node = Node{Label: smiDollar[1].id, Type: NodeObjectID, IDs: smiDollar[10].subidList}
+			smiVAL.node = Node{Label: smiDollar[1].id, Type: NodeObjectID, IDs: smiDollar[10].subidList, Line: smiDollar[1].line, Status: smiDollar[4].id,
+				Description: smiDollar[6].text, Reference: smiDollar[7].text}
 		}
 	case 124:
 		smiDollar = smiS[smipt-21 : smipt+1]
-//line smi.y:809
+//line smi.y:838
 		{
-			smiVAL.node = Node{Label: smiDollar[1].id, Type: NodeObjectType, IDs: smiDollar[20].subidList}
+			syntax := smiDollar[4].syntax
+			smiVAL.node = Node{Label: smiDollar[1].id, Type: NodeObjectType, IDs: smiDollar[20].subidList, Line: smiDollar[1].line,
+				Syntax: &syntax, Units: smiDollar[5].text, Access: smiDollar[6].id, Status: smiDollar[10].id, Description: smiDollar[11].text,
+				Reference: smiDollar[13].text, Index: smiDollar[15].indexList, Augments: smiDollar[14].id}
 		}
 	case 125:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:815
+//line smi.y:847
 		{
+			smiVAL.text = ""
 		}
 	case 126:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:818
+//line smi.y:851
 		{
+			smiVAL.text = smiDollar[2].text
 		}
 	case 127:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:823
+//line smi.y:857
 		{
 		}
 	case 128:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:826
+//line smi.y:860
 		{
 		}
 	case 129:
 		smiDollar = smiS[smipt-11 : smipt+1]
-//line smi.y:833
+//line smi.y:867
 		{
 		}
 	case 130:
 		smiDollar = smiS[smipt-4 : smipt+1]
-//line smi.y:838
+//line smi.y:872
 		{
 		}
 	case 131:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:841
+//line smi.y:875
 		{
 		}
 	case 132:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:846
+//line smi.y:880
 		{
 		}
 	case 133:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:849
+//line smi.y:883
 		{
 		}
 	case 134:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:854
+//line smi.y:888
 		{
 		}
 	case 135:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:859
+//line smi.y:893
 		{
 		}
 	case 136:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:862
+//line smi.y:896
 		{
 		}
 	case 137:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:866
+//line smi.y:900
 		{
+			smiVAL.id = smiDollar[1].id
 		}
 	case 138:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:869
+//line smi.y:904
 		{
+			smiVAL.id = smiDollar[1].id
 		}
 	case 139:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:872
+//line smi.y:908
 		{
+			smiVAL.id = ""
 		}
 	case 140:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:876
+//line smi.y:914
 		{
+			smiVAL.id = smiDollar[2].id
 		}
 	case 141:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:880
+//line smi.y:920
 		{
 		}
 	case 142:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:883
+//line smi.y:923
 		{
 		}
 	case 143:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:887
+//line smi.y:927
 		{
 		}
 	case 144:
 		smiDollar = smiS[smipt-5 : smipt+1]
-//line smi.y:890
+//line smi.y:930
 		{
 		}
 	case 145:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:892
+//line smi.y:932
 		{
 		}
 	case 146:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:896
+//line smi.y:936
 		{
 		}
 	case 147:
 		smiDollar = smiS[smipt-5 : smipt+1]
-//line smi.y:899
+//line smi.y:939
 		{
 		}
 	case 148:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:901
+//line smi.y:941
 		{
 		}
 	case 149:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:906
+//line smi.y:946
 		{
 		}
 	case 150:
 		smiDollar = smiS[smipt-5 : smipt+1]
-//line smi.y:909
+//line smi.y:949
 		{
 		}
 	case 151:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:911
+//line smi.y:951
 		{
 		}
 	case 152:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:915
+//line smi.y:955
 		{
 		}
 	case 153:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:917
+//line smi.y:957
 		{
 		}
 	case 154:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:921
+//line smi.y:961
 		{
 		}
 	case 155:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:924
+//line smi.y:964
 		{
 		}
 	case 156:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:929
+//line smi.y:969
 		{
 		}
 	case 157:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:933
+//line smi.y:973
 		{
 		}
 	case 158:
 		smiDollar = smiS[smipt-5 : smipt+1]
-//line smi.y:936
+//line smi.y:976
 		{
 		}
 	case 159:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:938
+//line smi.y:978
 		{
 		}
 	case 160:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:942
+//line smi.y:982
 		{
 		}
 	case 161:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:945
+//line smi.y:985
 		{
 		}
 	case 162:
 		smiDollar = smiS[smipt-4 : smipt+1]
-//line smi.y:950
+//line smi.y:990
 		{
 		}
 	case 163:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:956
+//line smi.y:996
 		{
 		}
 	case 164:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:959
+//line smi.y:999
 		{
+			smiVAL.id = smiDollar[3].id
 		}
 	case 165:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:961
+//line smi.y:1003
 		{
 		}
 	case 166:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:964
+//line smi.y:1006
 		{
+			smiVAL.id = smiDollar[3].id
 		}
 	case 167:
 		smiDollar = smiS[smipt-12 : smipt+1]
-//line smi.y:975
+//line smi.y:1019
 		{
-			smiVAL.node = Node{Label: smiDollar[1].id, Type: NodeNotification, IDs: smiDollar[11].subidList}
+			smiVAL.node = Node{Label: smiDollar[1].id, Type: NodeNotification, IDs: smiDollar[11].subidList, Line: smiDollar[1].line, Objects: smiDollar[3].idList,
+				Status: smiDollar[5].id, Description: smiDollar[7].text, Reference: smiDollar[8].text}
 		}
 	case 168:
 		smiDollar = smiS[smipt-16 : smipt+1]
-//line smi.y:990
+//line smi.y:1035
 		{
-			smiVAL.node = Node{Label: smiDollar[1].id, Type: NodeModuleID, IDs: smiDollar[15].subidList}
+			smiVAL.node = Node{Label: smiDollar[1].id, Type: NodeModuleID, IDs: smiDollar[15].subidList, Line: smiDollar[1].line, Description: smiDollar[11].text}
 		}
 	case 169:
 		smiDollar = smiS[smipt-4 : smipt+1]
-//line smi.y:996
+//line smi.y:1041
 		{
 		}
 	case 170:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:999
+//line smi.y:1044
 		{
 		}
 	case 171:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1004
+//line smi.y:1049
 		{
 		}
 	case 172:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1009
+//line smi.y:1054
 		{
 		}
 	case 173:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:1012
+//line smi.y:1057
 		{
 		}
 	case 174:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1017
+//line smi.y:1062
 		{
 		}
 	case 175:
 		smiDollar = smiS[smipt-4 : smipt+1]
-//line smi.y:1020
+//line smi.y:1065
 		{
 		}
 	case 176:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1025
+//line smi.y:1070
 		{
+			smiVAL.syntax = smiDollar[1].syntax
 		}
 	case 177:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1028
+//line smi.y:1074
 		{
+			smiVAL.syntax = smiDollar[2].syntax
 		}
 	case 178:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1031
+//line smi.y:1078
 		{
+			smiVAL.syntax = smiDollar[1].syntax
 		}
 	case 179:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1034
+//line smi.y:1082
 		{
+			smiVAL.syntax = smiDollar[1].syntax
 		}
 	case 180:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1037
+//line smi.y:1086
 		{
+			smiVAL.syntax = smiDollar[1].syntax
 		}
 	case 181:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1040
+//line smi.y:1090
 		{
+			smiVAL.syntax = smiDollar[1].syntax
 		}
 	case 182:
 		smiDollar = smiS[smipt-5 : smipt+1]
-//line smi.y:1045
+//line smi.y:1096
 		{
 		}
 	case 183:
 		smiDollar = smiS[smipt-5 : smipt+1]
-//line smi.y:1047
+//line smi.y:1098
 		{
 		}
 	case 184:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1055
+//line smi.y:1106
 		{
 		}
 	case 185:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1057
+//line smi.y:1108
 		{
 		}
 	case 186:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1062
+//line smi.y:1113
 		{
 		}
 	case 187:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1071
+//line smi.y:1122
 		{
+			smiVAL.syntax = Syntax{Type: "INTEGER"}
 		}
 	case 188:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1074
+//line smi.y:1126
 		{
 		}
 	case 189:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:1077
+//line smi.y:1129
 		{
+			smiVAL.syntax = Syntax{Type: "INTEGER", Ranges: smiDollar[3].ranges}
 		}
 	case 190:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1080
+//line smi.y:1133
 		{
 		}
 	case 191:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:1083
+//line smi.y:1136
 		{
+			smiVAL.syntax = Syntax{Type: "INTEGER", Enums: smiDollar[3].namedNumbers}
 		}
 	case 192:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1086
+//line smi.y:1140
 		{
+			smiVAL.syntax = Syntax{Type: "Integer32"}
 		}
 	case 193:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1089
+//line smi.y:1144
 		{
 		}
 	case 194:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:1092
+//line smi.y:1147
 		{
+			smiVAL.syntax = Syntax{Type: "Integer32", Ranges: smiDollar[3].ranges}
 		}
 	case 195:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1095
+//line smi.y:1151
 		{
 		}
 	case 196:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:1098
+//line smi.y:1154
 		{
+			smiVAL.syntax = Syntax{Type: smiDollar[1].id, Enums: smiDollar[3].namedNumbers}
 		}
 	case 197:
 		smiDollar = smiS[smipt-4 : smipt+1]
-//line smi.y:1101
+//line smi.y:1158
 		{
+			smiVAL.syntax = Syntax{Type: smiDollar[3].id, Enums: smiDollar[4].namedNumbers}
 		}
 	case 198:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1104
+//line smi.y:1162
 		{
+			smiVAL.syntax = Syntax{Type: smiDollar[1].id, Ranges: smiDollar[2].ranges}
 		}
 	case 199:
 		smiDollar = smiS[smipt-4 : smipt+1]
-//line smi.y:1107
+//line smi.y:1166
 		{
+			smiVAL.syntax = Syntax{Type: smiDollar[3].id, Ranges: smiDollar[4].ranges}
 		}
 	case 200:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1110
+//line smi.y:1170
 		{
+			smiVAL.syntax = Syntax{Type: "OCTET STRING"}
 		}
 	case 201:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1113
+//line smi.y:1174
 		{
 		}
 	case 202:
 		smiDollar = smiS[smipt-4 : smipt+1]
-//line smi.y:1116
+//line smi.y:1177
 		{
+			smiVAL.syntax = Syntax{Type: "OCTET STRING", Sizes: smiDollar[4].ranges}
 		}
 	case 203:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1119
+//line smi.y:1181
 		{
+			smiVAL.syntax = Syntax{Type: smiDollar[1].id, Sizes: smiDollar[2].ranges}
 		}
 	case 204:
 		smiDollar = smiS[smipt-4 : smipt+1]
-//line smi.y:1122
+//line smi.y:1185
 		{
+			smiVAL.syntax = Syntax{Type: smiDollar[3].id, Sizes: smiDollar[4].ranges}
 		}
 	case 205:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:1125
+//line smi.y:1189
 		{
+			smiVAL.syntax = smiDollar[3].syntax
+			smiVAL.syntax.Type = "OBJECT IDENTIFIER"
 		}
 	case 206:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1131
+//line smi.y:1197
 		{
 		}
 	case 207:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1134
+//line smi.y:1200
 		{
 		}
 	case 208:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1137
+//line smi.y:1203
 		{
 		}
 	case 209:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1140
+//line smi.y:1206
 		{
 		}
 	case 210:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1143
+//line smi.y:1209
 		{
 		}
 	case 211:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1146
+//line smi.y:1212
 		{
 		}
 	case 212:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1149
+//line smi.y:1215
 		{
 		}
 	case 213:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1152
+//line smi.y:1218
 		{
 		}
 	case 214:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:1171
+//line smi.y:1237
 		{
 		}
 	case 215:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1180
+//line smi.y:1246
 		{
 		}
 	case 216:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1183
+//line smi.y:1249
 		{
 		}
 	case 217:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:1186
+//line smi.y:1252
 		{
 		}
 	case 218:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:1189
+//line smi.y:1255
 		{
 		}
 	case 219:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1194
+//line smi.y:1260
 		{
+			smiVAL.syntax = smiDollar[2].syntax
+			smiVAL.syntax.Type = "IpAddress"
 		}
 	case 220:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1197
+//line smi.y:1265
 		{
+			smiVAL.syntax = Syntax{Type: "Counter32"}
 		}
 	case 221:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1200
+//line smi.y:1269
 		{
+			smiVAL.syntax = Syntax{Type: "Counter32", Ranges: smiDollar[2].ranges}
 		}
 	case 222:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1203
+//line smi.y:1273
 		{
+			smiVAL.syntax = Syntax{Type: "Gauge32"}
 		}
 	case 223:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1206
+//line smi.y:1277
 		{
+			smiVAL.syntax = Syntax{Type: "Gauge32", Ranges: smiDollar[2].ranges}
 		}
 	case 224:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1209
+//line smi.y:1281
 		{
+			smiVAL.syntax = Syntax{Type: "Unsigned32"}
 		}
 	case 225:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1212
+//line smi.y:1285
 		{
 		}
 	case 226:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:1215
+//line smi.y:1288
 		{
+			smiVAL.syntax = Syntax{Type: "Unsigned32", Ranges: smiDollar[3].ranges}
 		}
 	case 227:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1218
+//line smi.y:1292
 		{
+			smiVAL.syntax = smiDollar[2].syntax
+			smiVAL.syntax.Type = "TimeTicks"
 		}
 	case 228:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1221
+//line smi.y:1297
 		{
+			smiVAL.syntax = Syntax{Type: "Opaque"}
 		}
 	case 229:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1224
+//line smi.y:1301
 		{
+			smiVAL.syntax = Syntax{Type: "Opaque", Sizes: smiDollar[2].ranges}
 		}
 	case 230:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1227
+//line smi.y:1305
 		{
+			smiVAL.syntax = Syntax{Type: "Counter64"}
 		}
 	case 231:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1230
+//line smi.y:1309
 		{
+			smiVAL.syntax = Syntax{Type: "Counter64", Ranges: smiDollar[2].ranges}
 		}
 	case 232:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1233
+//line smi.y:1313
 		{
+			smiVAL.syntax = Syntax{Type: "Integer64"}
 		}
 	case 233:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1236
+//line smi.y:1317
 		{
+			smiVAL.syntax = Syntax{Type: "Integer64", Ranges: smiDollar[2].ranges}
 		}
 	case 234:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1239
+//line smi.y:1321
 		{
+			smiVAL.syntax = Syntax{Type: "Unsigned64"}
 		}
 	case 235:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1242
+//line smi.y:1325
 		{
+			smiVAL.syntax = Syntax{Type: "Unsigned64", Ranges: smiDollar[2].ranges}
 		}
 	case 236:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1251
+//line smi.y:1335
 		{
 		}
 	case 237:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1254
+//line smi.y:1338
 		{
 		}
 	case 238:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1257
+//line smi.y:1341
 		{
 		}
 	case 239:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1260
+//line smi.y:1344
 		{
 		}
 	case 240:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1263
+//line smi.y:1347
 		{
 		}
 	case 241:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1266
+//line smi.y:1350
 		{
 		}
 	case 242:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1269
+//line smi.y:1353
 		{
 		}
 	case 243:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1272
+//line smi.y:1356
 		{
 		}
 	case 244:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1275
+//line smi.y:1359
 		{
 		}
 	case 245:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1280
+//line smi.y:1364
 		{
+			smiVAL.syntax = Syntax{Ranges: smiDollar[1].ranges}
 		}
 	case 246:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1283
+//line smi.y:1368
 		{
+			smiVAL.syntax = Syntax{Sizes: smiDollar[1].ranges}
 		}
 	case 247:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1286
+//line smi.y:1372
 		{
+			smiVAL.syntax = Syntax{Enums: smiDollar[1].namedNumbers}
 		}
 	case 248:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:1289
+//line smi.y:1376
 		{
+			smiVAL.syntax = Syntax{}
 		}
 	case 249:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:1302
+//line smi.y:1390
 		{
+			smiVAL.ranges = smiDollar[2].ranges
 		}
 	case 250:
 		smiDollar = smiS[smipt-6 : smipt+1]
-//line smi.y:1312
+//line smi.y:1402
 		{
+			smiVAL.ranges = smiDollar[4].ranges
 		}
 	case 251:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1317
+//line smi.y:1408
 		{
+			smiVAL.ranges = []Range{smiDollar[1].rng}
 		}
 	case 252:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:1320
+//line smi.y:1412
 		{
+			smiVAL.ranges = append(smiDollar[1].ranges, smiDollar[3].rng)
 		}
 	case 253:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1325
+//line smi.y:1418
 		{
+			smiVAL.rng = Range{Min: smiDollar[1].number, Max: smiDollar[1].number}
 		}
 	case 254:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:1328
+//line smi.y:1422
 		{
+			smiVAL.rng = Range{Min: smiDollar[1].number, Max: smiDollar[3].number}
 		}
 	case 255:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1333
+//line smi.y:1428
 		{
+			smiVAL.number = int64(smiDollar[1].integer32)
 		}
 	case 256:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1336
+//line smi.y:1432
 		{
+			smiVAL.number = int64(smiDollar[1].unsigned32)
 		}
 	case 257:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1339
+//line smi.y:1436
 		{
+			smiVAL.number = smiDollar[1].integer64
 		}
 	case 258:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1342
+//line smi.y:1440
 		{
+			smiVAL.number = clampUint64(smiDollar[1].unsigned64)
 		}
 	case 259:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1345
+//line smi.y:1444
 		{
+			smiVAL.number = parseNumericString(smiDollar[1].text, 16)
 		}
 	case 260:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1348
+//line smi.y:1448
 		{
+			smiVAL.number = parseNumericString(smiDollar[1].text, 2)
 		}
 	case 261:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:1353
+//line smi.y:1454
 		{
+			smiVAL.namedNumbers = smiDollar[2].namedNumbers
 		}
 	case 262:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1358
+//line smi.y:1460
 		{
+			smiVAL.namedNumbers = []NamedNumber{smiDollar[1].namedNumber}
 		}
 	case 263:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:1361
+//line smi.y:1464
 		{
+			smiVAL.namedNumbers = append(smiDollar[1].namedNumbers, smiDollar[3].namedNumber)
 		}
 	case 264:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1366
+//line smi.y:1470
 		{
 		}
 	case 265:
 		smiDollar = smiS[smipt-5 : smipt+1]
-//line smi.y:1369
+//line smi.y:1473
 		{
+			smiVAL.namedNumber = NamedNumber{Name: smiDollar[1].id, Value: smiDollar[4].number}
 		}
 	case 266:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1374
+//line smi.y:1479
 		{
+			smiVAL.number = int64(smiDollar[1].unsigned32)
 		}
 	case 267:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1377
+//line smi.y:1483
 		{
+			smiVAL.number = int64(smiDollar[1].integer32)
 		}
 	case 268:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1382
+//line smi.y:1489
 		{
+			smiVAL.id = smiDollar[1].id
 		}
 	case 269:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1387
+//line smi.y:1495
 		{
 		}
 	case 270:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1392
+//line smi.y:1500
 		{
+			smiVAL.text = smiDollar[2].text
 		}
 	case 271:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:1395
+//line smi.y:1504
 		{
+			smiVAL.text = ""
 		}
 	case 272:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1400
+//line smi.y:1510
 		{
+			smiVAL.text = smiDollar[2].text
 		}
 	case 273:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:1403
+//line smi.y:1514
 		{
+			smiVAL.text = ""
 		}
 	case 274:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1408
+//line smi.y:1520
 		{
+			smiVAL.id = smiDollar[1].id
 		}
 	case 275:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1413
+//line smi.y:1526
 		{
 		}
 	case 276:
 		smiDollar = smiS[smipt-5 : smipt+1]
-//line smi.y:1416
+//line smi.y:1529
 		{
+			smiVAL.id = ""
 		}
 	case 277:
 		smiDollar = smiS[smipt-4 : smipt+1]
-//line smi.y:1419
+//line smi.y:1533
 		{
+			smiVAL.id = smiDollar[3].id
 		}
 	case 278:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1422
+//line smi.y:1537
 		{
 		}
 	case 279:
 		smiDollar = smiS[smipt-5 : smipt+1]
-//line smi.y:1425
+//line smi.y:1540
 		{
+			smiVAL.id = ""
 		}
 	case 280:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:1428
+//line smi.y:1544
 		{
+			smiVAL.id = ""
 		}
 	case 281:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1433
+//line smi.y:1550
 		{
 		}
 	case 282:
 		smiDollar = smiS[smipt-5 : smipt+1]
-//line smi.y:1436
+//line smi.y:1553
 		{
+			smiVAL.indexList = smiDollar[4].indexList
 		}
 	case 283:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:1439
+//line smi.y:1557
 		{
+			smiVAL.indexList = nil
 		}
 	case 284:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1444
+//line smi.y:1563
 		{
+			smiVAL.indexList = []IndexObject{smiDollar[1].indexObject}
 		}
 	case 285:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:1447
+//line smi.y:1567
 		{
+			smiVAL.indexList = append(smiDollar[1].indexList, smiDollar[3].indexObject)
 		}
 	case 286:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1452
+//line smi.y:1573
 		{
+			smiVAL.indexObject = IndexObject{Name: smiDollar[2].id, Implied: true}
 		}
 	case 287:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1455
+//line smi.y:1577
 		{
+			smiVAL.indexObject = IndexObject{Name: smiDollar[1].id}
 		}
 	case 288:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1460
+//line smi.y:1583
 		{
+			smiVAL.id = smiDollar[1].subidList[len(smiDollar[1].subidList)-1].Label
 		}
 	case 289:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1465
+//line smi.y:1589
 		{
+			smiVAL.id = smiDollar[1].subidList[len(smiDollar[1].subidList)-1].Label
 		}
 	case 290:
 		smiDollar = smiS[smipt-4 : smipt+1]
-//line smi.y:1470
+//line smi.y:1595
 		{
 		}
 	case 291:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:1473
+//line smi.y:1598
 		{
 		}
 	case 292:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1477
+//line smi.y:1602
 		{
 		}
 	case 293:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:1479
+//line smi.y:1604
 		{
 		}
 	case 294:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1484
+//line smi.y:1609
 		{
 		}
 	case 295:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:1486
+//line smi.y:1611
 		{
 		}
 	case 296:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1490
+//line smi.y:1615
 		{
 		}
 	case 297:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:1493
+//line smi.y:1618
 		{
 		}
 	case 298:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1498
+//line smi.y:1623
 		{
 		}
 	case 299:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1503
+//line smi.y:1628
 		{
 		}
 	case 300:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1508
+//line smi.y:1633
 		{
+			smiVAL.text = smiDollar[2].text
 		}
 	case 301:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:1511
+//line smi.y:1637
 		{
+			smiVAL.text = ""
 		}
 	case 302:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1515
+//line smi.y:1643
 		{
 		}
 	case 303:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:1517
+//line smi.y:1645
 		{
 		}
 	case 304:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1521
+//line smi.y:1649
 		{
 		}
 	case 305:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1523
+//line smi.y:1651
 		{
 		}
 	case 306:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1527
+//line smi.y:1655
 		{
 		}
 	case 307:
 		smiDollar = smiS[smipt-5 : smipt+1]
-//line smi.y:1530
+//line smi.y:1658
 		{
 		}
 	case 308:
 		smiDollar = smiS[smipt-4 : smipt+1]
-//line smi.y:1535
+//line smi.y:1663
 		{
+			smiVAL.idList = smiDollar[3].idList
 		}
 	case 309:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:1538
+//line smi.y:1667
 		{
+			smiVAL.idList = nil
 		}
 	case 310:
 		smiDollar = smiS[smipt-4 : smipt+1]
-//line smi.y:1543
+//line smi.y:1673
 		{
+			smiVAL.idList = smiDollar[3].idList
 		}
 	case 311:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1548
+//line smi.y:1679
 		{
+			smiVAL.idList = []string{smiDollar[1].id}
 		}
 	case 312:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:1551
+//line smi.y:1683
 		{
+			smiVAL.idList = append(smiDollar[1].idList, smiDollar[3].id)
 		}
 	case 313:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1556
+//line smi.y:1689
 		{
+			smiVAL.id = smiDollar[1].subidList[len(smiDollar[1].subidList)-1].Label
 		}
 	case 314:
 		smiDollar = smiS[smipt-4 : smipt+1]
-//line smi.y:1561
+//line smi.y:1695
 		{
 		}
 	case 315:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1566
+//line smi.y:1700
 		{
 		}
 	case 316:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:1569
+//line smi.y:1703
 		{
 		}
 	case 317:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1574
+//line smi.y:1708
 		{
 		}
 	case 318:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1579
+//line smi.y:1713
 		{
+			smiVAL.text = smiDollar[1].text
 		}
 	case 319:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1584
+//line smi.y:1719
 		{
 		}
 	case 320:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1589
+//line smi.y:1724
 		{
 		}
 	case 321:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1595
+//line smi.y:1730
 		{
 			smiVAL.subidList = []SubID{smiDollar[1].subid}
 		}
 	case 322:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1600
+//line smi.y:1735
 		{
 			smiVAL.subidList = append(smiDollar[1].subidList, smiDollar[2].subid)
 		}
 	case 323:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1608
+//line smi.y:1743
 		{
 			smiVAL.subid = SubID{ID: -1, Label: smiDollar[1].id}
 		}
 	case 324:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1612
+//line smi.y:1747
 		{
 			smiVAL.subid = SubID{ID: int(smiDollar[1].unsigned32)}
 		}
 	case 325:
 		smiDollar = smiS[smipt-4 : smipt+1]
-//line smi.y:1616
+//line smi.y:1751
 		{
 			smiVAL.subid = SubID{int(smiDollar[3].unsigned32), smiDollar[1].id}
 		}
 	case 326:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1622
+//line smi.y:1757
 		{
 		}
 	case 327:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1626
+//line smi.y:1761
 		{
 		}
 	case 328:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1628
+//line smi.y:1763
 		{
 		}
 	case 329:
 		smiDollar = smiS[smipt-4 : smipt+1]
-//line smi.y:1632
+//line smi.y:1767
 		{
 		}
 	case 330:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1634
+//line smi.y:1769
 		{
 		}
 	case 331:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1638
+//line smi.y:1773
 		{
 		}
 	case 332:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:1641
+//line smi.y:1776
 		{
 		}
 	case 333:
 		smiDollar = smiS[smipt-9 : smipt+1]
-//line smi.y:1646
+//line smi.y:1781
 		{
 		}
 	case 334:
 		smiDollar = smiS[smipt-15 : smipt+1]
-//line smi.y:1650
+//line smi.y:1785
 		{
 		}
 	case 335:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1655
+//line smi.y:1790
 		{
 		}
 	case 336:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:1658
+//line smi.y:1793
 		{
 		}
 	case 337:
 		smiDollar = smiS[smipt-9 : smipt+1]
-//line smi.y:1663
+//line smi.y:1798
 		{
 		}
 	case 338:
 		smiDollar = smiS[smipt-15 : smipt+1]
-//line smi.y:1667
+//line smi.y:1802
 		{
 		}
 	case 339:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1672
+//line smi.y:1807
 		{
 		}
 	case 340:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:1675
+//line smi.y:1810
 		{
 		}
 	case 341:
 		smiDollar = smiS[smipt-8 : smipt+1]
-//line smi.y:1679
+//line smi.y:1814
 		{
 		}
 	case 342:
 		smiDollar = smiS[smipt-15 : smipt+1]
-//line smi.y:1684
+//line smi.y:1819
 		{
 		}
 	case 343:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1689
+//line smi.y:1824
 		{
 		}
 	case 344:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1694
+//line smi.y:1829
 		{
 		}
 	case 345:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1697
+//line smi.y:1832
 		{
 		}
 	case 346:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1702
+//line smi.y:1837
 		{
 		}
 	case 347:
 		smiDollar = smiS[smipt-5 : smipt+1]
-//line smi.y:1706
+//line smi.y:1841
 		{
 		}
 	case 348:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1711
+//line smi.y:1846
 		{
 		}
 	case 349:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1714
+//line smi.y:1849
 		{
 		}
 	case 350:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:1717
+//line smi.y:1852
 		{
 		}
 	case 351:
 		smiDollar = smiS[smipt-4 : smipt+1]
-//line smi.y:1722
+//line smi.y:1857
 		{
 		}
 	case 352:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:1725
+//line smi.y:1860
 		{
 		}
 	case 353:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1730
+//line smi.y:1865
 		{
 		}
 	case 354:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:1733
+//line smi.y:1868
 		{
 		}
 	case 355:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1738
+//line smi.y:1873
 		{
 		}
 	case 356:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1743
+//line smi.y:1878
 		{
 		}
 	case 357:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:1746
+//line smi.y:1881
 		{
 		}
 	case 358:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1751
+//line smi.y:1886
 		{
 		}
 	case 359:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1754
+//line smi.y:1889
 		{
 		}
 	case 360:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1759
+//line smi.y:1894
 		{
 		}
 	case 361:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1762
+//line smi.y:1897
 		{
 		}
 	case 362:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1767
+//line smi.y:1902
 		{
 		}
 	case 363:
 		smiDollar = smiS[smipt-5 : smipt+1]
-//line smi.y:1771
+//line smi.y:1906
 		{
 		}
 	case 364:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1776
+//line smi.y:1911
 		{
 		}
 	case 365:
 		smiDollar = smiS[smipt-8 : smipt+1]
-//line smi.y:1783
+//line smi.y:1918
 		{
 		}
 	case 366:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1788
+//line smi.y:1923
 		{
 		}
 	case 367:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:1791
+//line smi.y:1926
 		{
 		}
 	case 368:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1796
+//line smi.y:1931
 		{
 		}
 	case 369:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:1799
+//line smi.y:1934
 		{
 		}
 	case 370:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1804
+//line smi.y:1939
 		{
 		}
 	case 371:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1809
+//line smi.y:1944
 		{
 		}
 	case 372:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1812
+//line smi.y:1947
 		{
 		}
 	case 373:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:1815
+//line smi.y:1950
 		{
 		}
 	case 374:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1820
+//line smi.y:1955
 		{
 		}
 	case 375:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:1823
+//line smi.y:1958
 		{
 		}
 	case 376:
 		smiDollar = smiS[smipt-10 : smipt+1]
-//line smi.y:1828
+//line smi.y:1963
 		{
 		}
 	case 377:
 		smiDollar = smiS[smipt-17 : smipt+1]
-//line smi.y:1833
+//line smi.y:1968
 		{
 		}
 	case 378:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1838
+//line smi.y:1973
 		{
 		}
 	case 379:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:1840
+//line smi.y:1975
 		{
 		}
 	case 380:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1844
+//line smi.y:1979
 		{
 		}
 	case 381:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1846
+//line smi.y:1981
 		{
 		}
 	case 382:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1850
+//line smi.y:1985
 		{
 		}
 	case 383:
 		smiDollar = smiS[smipt-8 : smipt+1]
-//line smi.y:1854
+//line smi.y:1989
 		{
 		}
 	case 384:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1859
+//line smi.y:1994
 		{
 		}
 	case 385:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:1862
+//line smi.y:1997
 		{
 		}
 	case 386:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1867
+//line smi.y:2002
 		{
 		}
 	case 387:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1872
+//line smi.y:2007
 		{
 		}
 	case 388:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1875
+//line smi.y:2010
 		{
 		}
 	case 389:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1880
+//line smi.y:2015
 		{
 		}
 	case 390:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:1882
+//line smi.y:2017
 		{
 		}
 	case 391:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1886
+//line smi.y:2021
 		{
 		}
 	case 392:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1888
+//line smi.y:2023
 		{
 		}
 	case 393:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1892
+//line smi.y:2027
 		{
 		}
 	case 394:
 		smiDollar = smiS[smipt-8 : smipt+1]
-//line smi.y:1899
+//line smi.y:2034
 		{
 		}
 	case 395:
 		smiDollar = smiS[smipt-11 : smipt+1]
-//line smi.y:1902
+//line smi.y:2037
 		{
 		}
 	case 396:
 		smiDollar = smiS[smipt-2 : smipt+1]
-//line smi.y:1907
+//line smi.y:2042
 		{
 		}
 	case 397:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:1909
+//line smi.y:2044
 		{
 		}
 	case 398:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1913
+//line smi.y:2048
 		{
 		}
 	case 399:
 		smiDollar = smiS[smipt-4 : smipt+1]
-//line smi.y:1918
+//line smi.y:2053
 		{
 		}
 	case 400:
 		smiDollar = smiS[smipt-0 : smipt+1]
-//line smi.y:1921
+//line smi.y:2056
 		{
 		}
 	case 401:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1925
+//line smi.y:2060
 		{
 		}
 	case 402:
 		smiDollar = smiS[smipt-3 : smipt+1]
-//line smi.y:1927
+//line smi.y:2062
 		{
 		}
 	case 403:
 		smiDollar = smiS[smipt-1 : smipt+1]
-//line smi.y:1931
+//line smi.y:2066
 		{
 		}
 	}